@@ -0,0 +1,106 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package bennyfi
+
+import (
+	eos "github.com/eoscanada/eos-go"
+)
+
+type PauseArgs struct {
+	Pause int64 `json:"pause"`
+}
+
+type SetAuthLevelArgs struct {
+	Authorizer eos.AccountName `json:"authorizer"`
+	Account    eos.AccountName `json:"account"`
+	Level      uint64          `json:"auth_level"`
+	Notes      string          `json:"notes"`
+}
+
+type EraseAuthArgs struct {
+	Authorizer eos.AccountName `json:"authorizer"`
+	Account    eos.AccountName `json:"account"`
+}
+
+type SetTokenRoleData struct {
+	Authorizer    eos.AccountName `json:"authorizer"`
+	Symbol        string          `json:"symbol"`
+	TokenContract eos.AccountName `json:"token_contract"`
+	TokenRole     eos.Name        `json:"token_role"`
+	MinValue      string          `json:"min_value"`
+	MaxValue      string          `json:"max_value"`
+}
+
+type EraseTokenArgs struct {
+	Authorizer eos.AccountName `json:"authorizer"`
+	Symbol     string          `json:"symbol"`
+}
+
+type EraseTokenRoleArgs struct {
+	Authorizer eos.AccountName `json:"authorizer"`
+	Symbol     string          `json:"symbol"`
+	TokenRole  eos.Name        `json:"token_role"`
+}
+
+type SetterArgs struct {
+	Setter eos.AccountName `json:"setter"`
+	Key    string          `json:"key"`
+	Value  *FlexValue      `json:"value"`
+}
+
+type EraseSettingArgs struct {
+	Setter eos.AccountName `json:"setter"`
+	Key    string          `json:"key"`
+}
+
+// NewActionArgs returns a pointer to the type that holds the data of the specified bennyfi action,
+// nil if the action has no registered type
+func NewActionArgs(action eos.ActionName) interface{} {
+	switch action {
+	case "pause":
+		return &PauseArgs{}
+	case "setauth":
+		return &Auth{}
+	case "setauthlevel":
+		return &SetAuthLevelArgs{}
+	case "eraseauth":
+		return &EraseAuthArgs{}
+	case "settoken":
+		return &AuthToken{}
+	case "settokenrole":
+		return &SetTokenRoleData{}
+	case "erasetoken":
+		return &EraseTokenArgs{}
+	case "erasetknrole":
+		return &EraseTokenRoleArgs{}
+	case "setsetting", "appndsetting", "clipsetting":
+		return &SetterArgs{}
+	case "erasesetting":
+		return &EraseSettingArgs{}
+	case "newterm":
+		return &NewTermArgs{}
+	case "newround":
+		return &NewRoundArgs{}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,151 @@
+package bennyfi_test
+
+import (
+	"testing"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/eoscanada/eos-go/ecc"
+	"github.com/eoscanada/eos-go/system"
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+	"github.com/sebastianmontero/bennyfi-go-client/chain"
+	"gotest.tools/assert"
+)
+
+func newTestContract(t *testing.T) *bennyfi.BennyfiContract {
+	contract, err := bennyfi.NewBennyfiContract(nil, "bennyfi", chain.Telos())
+	assert.NilError(t, err)
+	return contract
+}
+
+func TestNewBennyfiContractValidatesChain(t *testing.T) {
+	invalid := chain.Telos()
+	invalid.RexContract = ""
+	_, err := bennyfi.NewBennyfiContract(nil, "bennyfi", invalid)
+	assert.ErrorContains(t, err, "has no rex contract")
+
+	rexContract, err := newTestContract(t).RexContract()
+	assert.NilError(t, err)
+	assert.Equal(t, rexContract.ContractName, "eosio")
+}
+
+func TestConfigureOpenPermissionActions(t *testing.T) {
+	contract := newTestContract(t)
+	publicKey, err := ecc.NewPublicKey("EOS6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV")
+	assert.NilError(t, err)
+	actions := contract.ConfigureOpenPermissionActions(&publicKey)
+	assert.Equal(t, len(actions), len(bennyfi.OpenActions)+1)
+
+	updateAuth := actions[0]
+	assert.Equal(t, updateAuth.Account, eos.AN("eosio"))
+	assert.Equal(t, updateAuth.Name, eos.ActN("updateauth"))
+	assert.DeepEqual(t, updateAuth.Authorization, []eos.PermissionLevel{{Actor: "bennyfi", Permission: "active"}})
+	data, ok := updateAuth.Data.(system.UpdateAuth)
+	assert.Assert(t, ok)
+	assert.Equal(t, data.Account, eos.AN("bennyfi"))
+	assert.Equal(t, data.Permission, chain.Telos().CrankPermission)
+	assert.Equal(t, data.Parent, eos.PN("active"))
+	assert.Equal(t, data.Auth.Threshold, uint32(1))
+	assert.Equal(t, len(data.Auth.Keys), 1)
+	assert.Equal(t, data.Auth.Keys[0].PublicKey.String(), publicKey.String())
+	assert.DeepEqual(t, data.Auth.Accounts, []eos.PermissionLevelWeight{{
+		Permission: eos.PermissionLevel{Actor: "bennyfi", Permission: "active"},
+		Weight:     1,
+	}})
+
+	for i, openAction := range bennyfi.OpenActions {
+		linkAuth := actions[i+1]
+		assert.Equal(t, linkAuth.Name, eos.ActN("linkauth"))
+		assert.DeepEqual(t, linkAuth.Data, system.LinkAuth{
+			Account:     "bennyfi",
+			Code:        "bennyfi",
+			Type:        openAction,
+			Requirement: chain.Telos().CrankPermission,
+		})
+	}
+}
+
+func TestProposeActionBuilders(t *testing.T) {
+	contract := newTestContract(t)
+	symbol := eos.Symbol{Precision: 4, Symbol: "TLOS"}
+
+	action := contract.PauseAction(bennyfi.PAUSED)
+	assert.Equal(t, action.ActionName, eos.ActN("pause"))
+	assert.Equal(t, action.PermissionLevel, eos.AN("bennyfi"))
+	assert.DeepEqual(t, action.Data, map[string]interface{}{"pause": bennyfi.PAUSED})
+
+	action = contract.SetAuthLevelAction("admin", "alice", 20, "enroller")
+	assert.Equal(t, action.ActionName, eos.ActN("setauthlevel"))
+	assert.Equal(t, action.PermissionLevel, eos.AN("admin"))
+	assert.DeepEqual(t, action.Data, map[string]interface{}{
+		"authorizer": eos.AN("admin"),
+		"account":    eos.AN("alice"),
+		"auth_level": uint64(20),
+		"notes":      "enroller",
+	})
+
+	auth := &bennyfi.Auth{Authorizer: "admin", Account: "alice"}
+	action = contract.SetAuthAction(auth)
+	assert.Equal(t, action.ActionName, eos.ActN("setauth"))
+	assert.Equal(t, action.PermissionLevel, eos.AN("admin"))
+	assert.Equal(t, action.Data, auth)
+
+	action = contract.EraseAuthAction("admin", "alice")
+	assert.Equal(t, action.ActionName, eos.ActN("eraseauth"))
+	assert.DeepEqual(t, action.Data, map[string]interface{}{
+		"authorizer": eos.AN("admin"),
+		"account":    eos.AN("alice"),
+	})
+
+	authToken := &bennyfi.AuthToken{
+		Authorizer:    "admin",
+		Symbol:        symbol.String(),
+		TokenContract: "eosio.token",
+		TokenRoles:    bennyfi.TokenRoles{bennyfi.NewTokenRole("entrystake", 10000, 20000, symbol)},
+	}
+	action = contract.SetTokenAction(authToken)
+	assert.Equal(t, action.ActionName, eos.ActN("settoken"))
+	assert.Equal(t, action.PermissionLevel, eos.AN("admin"))
+	assert.DeepEqual(t, action.Data, map[string]interface{}{
+		"authorizer":     eos.AN("admin"),
+		"symbol":         "4,TLOS",
+		"token_contract": eos.AN("eosio.token"),
+		"token_roles":    authToken.TokenRoles,
+	})
+
+	action = contract.SetTokenRoleAction(&bennyfi.SetTokenRoleArgs{
+		Authorizer:    "admin",
+		TokenContract: "eosio.token",
+		TokenRole:     "entrystake",
+		MinValue:      eos.Asset{Amount: 10000, Symbol: symbol},
+		MaxValue:      eos.Asset{Amount: 20000, Symbol: symbol},
+	})
+	assert.Equal(t, action.ActionName, eos.ActN("settokenrole"))
+	assert.DeepEqual(t, action.Data, map[string]interface{}{
+		"authorizer":     eos.AN("admin"),
+		"symbol":         "4,TLOS",
+		"token_contract": eos.AN("eosio.token"),
+		"token_role":     eos.Name("entrystake"),
+		"min_value":      "1.0000 TLOS",
+		"max_value":      "2.0000 TLOS",
+	})
+
+	action = contract.EraseTokenAction("admin", symbol)
+	assert.Equal(t, action.ActionName, eos.ActN("erasetoken"))
+	assert.DeepEqual(t, action.Data, map[string]interface{}{
+		"authorizer": eos.AN("admin"),
+		"symbol":     "4,TLOS",
+	})
+
+	action = contract.EraseTokenRoleAction("admin", symbol, "entrystake")
+	assert.Equal(t, action.ActionName, eos.ActN("erasetknrole"))
+	assert.DeepEqual(t, action.Data, map[string]interface{}{
+		"authorizer": eos.AN("admin"),
+		"symbol":     "4,TLOS",
+		"token_role": eos.Name("entrystake"),
+	})
+
+	action = contract.NewTermAction(&bennyfi.NewTermArgs{RoundManager: "manager", TermName: "term1"})
+	assert.Equal(t, action.ActionName, eos.ActN("newterm"))
+	assert.Equal(t, action.PermissionLevel, eos.AN("manager"))
+	assert.Equal(t, action.Data.(map[string]interface{})["term_name"], "term1")
+}
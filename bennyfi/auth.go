@@ -23,8 +23,10 @@ package bennyfi
 
 import (
 	"fmt"
+	"time"
 
 	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/internal/tablepage"
 )
 
 var (
@@ -47,24 +49,40 @@ type Auth struct {
 }
 
 func (m *BennyfiContract) SetAuth(auth *Auth) (string, error) {
-	_, err := m.Contract.ExecAction(auth.Authorizer, "setauth", auth)
+	_, err := m.Exec(m.SetAuthAction(auth))
 	if err != nil {
 		return "", err
 	}
 	return "", nil
 }
 
+func (m *BennyfiContract) ProposeSetAuth(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, auth *Auth) (string, error) {
+	return m.Propose(proposerName, requested, expireIn, m.SetAuthAction(auth))
+}
+
+func (m *BennyfiContract) SetAuthAction(auth *Auth) *ContractAction {
+	return NewContractAction(auth.Authorizer, "setauth", auth)
+}
+
 func (m *BennyfiContract) SetAuthLevel(authorizer, account eos.AccountName, level uint64, notes string) (string, error) {
+	_, err := m.Exec(m.SetAuthLevelAction(authorizer, account, level, notes))
+	if err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+func (m *BennyfiContract) ProposeSetAuthLevel(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, authorizer, account eos.AccountName, level uint64, notes string) (string, error) {
+	return m.Propose(proposerName, requested, expireIn, m.SetAuthLevelAction(authorizer, account, level, notes))
+}
+
+func (m *BennyfiContract) SetAuthLevelAction(authorizer, account eos.AccountName, level uint64, notes string) *ContractAction {
 	actionData := make(map[string]interface{})
 	actionData["authorizer"] = authorizer
 	actionData["account"] = account
 	actionData["auth_level"] = level
 	actionData["notes"] = notes
-	_, err := m.Contract.ExecAction(authorizer, "setauthlevel", actionData)
-	if err != nil {
-		return "", err
-	}
-	return "", nil
+	return NewContractAction(authorizer, "setauthlevel", actionData)
 }
 
 func (m *BennyfiContract) SetProfile(account eos.AccountName, displayName, avatar string) (string, error) {
@@ -80,16 +98,24 @@ func (m *BennyfiContract) SetProfile(account eos.AccountName, displayName, avata
 }
 
 func (m *BennyfiContract) EraseAuth(authorizer, account eos.AccountName) (string, error) {
-	actionData := make(map[string]interface{})
-	actionData["authorizer"] = authorizer
-	actionData["account"] = account
-	_, err := m.Contract.ExecAction(string(authorizer), "eraseauth", actionData)
+	_, err := m.Exec(m.EraseAuthAction(authorizer, account))
 	if err != nil {
 		return "", err
 	}
 	return "", nil
 }
 
+func (m *BennyfiContract) ProposeEraseAuth(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, authorizer, account eos.AccountName) (string, error) {
+	return m.Propose(proposerName, requested, expireIn, m.EraseAuthAction(authorizer, account))
+}
+
+func (m *BennyfiContract) EraseAuthAction(authorizer, account eos.AccountName) *ContractAction {
+	actionData := make(map[string]interface{})
+	actionData["authorizer"] = authorizer
+	actionData["account"] = account
+	return NewContractAction(string(authorizer), "eraseauth", actionData)
+}
+
 func (m *BennyfiContract) GetAuths() ([]Auth, error) {
 	return m.GetAuthsReq(nil)
 }
@@ -127,3 +153,24 @@ func (m *BennyfiContract) GetAuthsReq(req *eos.GetTableRowsRequest) ([]Auth, err
 	}
 	return auths, nil
 }
+
+// GetAllAuths pages through the auths table, the lower bound of the next page is the numeric value of the
+// last account name plus one
+func (m *BennyfiContract) GetAllAuths() ([]Auth, error) {
+	auths := make([]Auth, 0)
+	err := tablepage.ReadAll(func(lowerBound string) (int, bool, error) {
+		var page []Auth
+		more, err := tablepage.GetTableRows(m.Contract, eos.GetTableRowsRequest{
+			Table:      "auths",
+			LowerBound: lowerBound,
+		}, &page)
+		auths = append(auths, page...)
+		return len(page), more, err
+	}, func() (string, error) {
+		return tablepage.AfterName(string(auths[len(auths)-1].Account)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return auths, nil
+}
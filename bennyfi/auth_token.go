@@ -23,6 +23,7 @@ package bennyfi
 
 import (
 	"fmt"
+	"time"
 
 	eos "github.com/eoscanada/eos-go"
 )
@@ -82,16 +83,31 @@ func (m *SetTokenRoleArgs) GetTokenRole() *TokenRole {
 }
 
 func (m *BennyfiContract) SetToken(authToken *AuthToken) (string, error) {
+	return m.Exec(m.SetTokenAction(authToken))
+}
+
+func (m *BennyfiContract) ProposeSetToken(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, authToken *AuthToken) (string, error) {
+	return m.Propose(proposerName, requested, expireIn, m.SetTokenAction(authToken))
+}
+
+func (m *BennyfiContract) SetTokenAction(authToken *AuthToken) *ContractAction {
 	actionData := make(map[string]interface{})
 	actionData["authorizer"] = authToken.Authorizer
 	actionData["symbol"] = authToken.Symbol
 	actionData["token_contract"] = authToken.TokenContract
 	actionData["token_roles"] = authToken.TokenRoles
-
-	return m.ExecAction(authToken.Authorizer, "settoken", actionData)
+	return NewContractAction(authToken.Authorizer, "settoken", actionData)
 }
 
 func (m *BennyfiContract) SetTokenRole(args *SetTokenRoleArgs) (string, error) {
+	return m.Exec(m.SetTokenRoleAction(args))
+}
+
+func (m *BennyfiContract) ProposeSetTokenRole(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, args *SetTokenRoleArgs) (string, error) {
+	return m.Propose(proposerName, requested, expireIn, m.SetTokenRoleAction(args))
+}
+
+func (m *BennyfiContract) SetTokenRoleAction(args *SetTokenRoleArgs) *ContractAction {
 	actionData := make(map[string]interface{})
 	actionData["authorizer"] = args.Authorizer
 	actionData["symbol"] = args.MinValue.Symbol.String()
@@ -99,25 +115,38 @@ func (m *BennyfiContract) SetTokenRole(args *SetTokenRoleArgs) (string, error) {
 	actionData["token_role"] = args.TokenRole
 	actionData["min_value"] = args.MinValue.String()
 	actionData["max_value"] = args.MaxValue.String()
-
-	return m.ExecAction(args.Authorizer, "settokenrole", actionData)
+	return NewContractAction(args.Authorizer, "settokenrole", actionData)
 }
 
 func (m *BennyfiContract) EraseToken(authorizer eos.AccountName, symbol eos.Symbol) (string, error) {
+	return m.Exec(m.EraseTokenAction(authorizer, symbol))
+}
+
+func (m *BennyfiContract) ProposeEraseToken(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, authorizer eos.AccountName, symbol eos.Symbol) (string, error) {
+	return m.Propose(proposerName, requested, expireIn, m.EraseTokenAction(authorizer, symbol))
+}
+
+func (m *BennyfiContract) EraseTokenAction(authorizer eos.AccountName, symbol eos.Symbol) *ContractAction {
 	actionData := make(map[string]interface{})
 	actionData["authorizer"] = authorizer
 	actionData["symbol"] = symbol.String()
-
-	return m.ExecAction(authorizer, "erasetoken", actionData)
+	return NewContractAction(authorizer, "erasetoken", actionData)
 }
 
 func (m *BennyfiContract) EraseTokenRole(authorizer eos.AccountName, symbol eos.Symbol, tokenRole eos.Name) (string, error) {
+	return m.Exec(m.EraseTokenRoleAction(authorizer, symbol, tokenRole))
+}
+
+func (m *BennyfiContract) ProposeEraseTokenRole(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, authorizer eos.AccountName, symbol eos.Symbol, tokenRole eos.Name) (string, error) {
+	return m.Propose(proposerName, requested, expireIn, m.EraseTokenRoleAction(authorizer, symbol, tokenRole))
+}
+
+func (m *BennyfiContract) EraseTokenRoleAction(authorizer eos.AccountName, symbol eos.Symbol, tokenRole eos.Name) *ContractAction {
 	actionData := make(map[string]interface{})
 	actionData["authorizer"] = authorizer
 	actionData["symbol"] = symbol.String()
 	actionData["token_role"] = tokenRole
-
-	return m.ExecAction(authorizer, "erasetknrole", actionData)
+	return NewContractAction(authorizer, "erasetknrole", actionData)
 }
 
 func (m *BennyfiContract) GetTokens() ([]AuthToken, error) {
@@ -0,0 +1,414 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package bennyfi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/nft"
+)
+
+const (
+	BadgeWinner      = "winner"
+	BadgeParticipant = "participant"
+)
+
+var (
+	// BadgeRoundStates are the states in which the winners of a round have been drawn
+	BadgeRoundStates = []eos.Name{RoundClosed, RoundUnlocked, RoundUnlockedUnstaked}
+	// BadgeFormats are the attributes the badge schema must define
+	BadgeFormats = []*nft.Format{
+		{Name: "badge", Type: "string"},
+		{Name: "round_id", Type: "uint64"},
+		{Name: "round_name", Type: "string"},
+		{Name: "participant", Type: "string"},
+		{Name: "prize", Type: "string"},
+		{Name: "entry_position", Type: "uint64"},
+	}
+)
+
+// BadgeConfig defines where the badges are minted, participation badges are only minted if
+// MintParticipation is set, a template id of -1 mints the badges without template. Every badge is appended to
+// LedgerFile as pending before it is minted and again once minted, when it is not set or does not exist yet
+// the minted badges are found by scanning the assets of the badge schema
+type BadgeConfig struct {
+	Minter                  eos.AccountName
+	CollectionName          eos.Name
+	SchemaName              eos.Name
+	WinnerTemplateId        int32
+	ParticipationTemplateId int32
+	MintParticipation       bool
+	LedgerFile              string
+}
+
+// BadgeKey identifies a badge, the participant is stored in the badge so that transferred badges are
+// still found and not minted again
+type BadgeKey struct {
+	RoundID     uint64          `json:"round_id"`
+	Participant eos.AccountName `json:"participant"`
+	Badge       string          `json:"badge"`
+}
+
+// BadgeLedgerRecord is a line of the badge ledger, Pending records are written before the badge is minted
+type BadgeLedgerRecord struct {
+	BadgeKey
+	Pending bool `json:"pending,omitempty"`
+}
+
+// PlanRoundBadges returns the mint args of the badges of the round that have not been minted, winners get a
+// winner badge and, if enabled, the rest of the entries that did not exit early get a participation badge
+func PlanRoundBadges(config *BadgeConfig, round *Round, entries []Entry, minted map[BadgeKey]bool) ([]*nft.MintAssetArgs, error) {
+	if !containsName(BadgeRoundStates, round.CurrentState) {
+		return nil, fmt.Errorf("round: %v in state: %v has not been closed", round.RoundID, round.CurrentState)
+	}
+	mints := make([]*nft.MintAssetArgs, 0)
+	winners := make(map[eos.AccountName]bool)
+	for _, winner := range round.Winners {
+		winners[winner.Participant] = true
+		key := BadgeKey{RoundID: round.RoundID, Participant: winner.Participant, Badge: BadgeWinner}
+		if minted[key] {
+			continue
+		}
+		mints = append(mints, newBadgeMint(config, config.WinnerTemplateId, round, key, winner.Prize, winner.EntryPosition))
+	}
+	if !config.MintParticipation {
+		return mints, nil
+	}
+	sorted := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.RoundID == round.RoundID && entry.EntryStatus != EntryEarlyExit && !winners[entry.Participant] {
+			sorted = append(sorted, entry)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Position < sorted[j].Position
+	})
+	for _, entry := range sorted {
+		key := BadgeKey{RoundID: round.RoundID, Participant: entry.Participant, Badge: BadgeParticipant}
+		if minted[key] {
+			continue
+		}
+		mints = append(mints, newBadgeMint(config, config.ParticipationTemplateId, round, key, entry.Prize, entry.Position))
+	}
+	return mints, nil
+}
+
+func newBadgeMint(config *BadgeConfig, templateId int32, round *Round, key BadgeKey, prize string, position uint64) *nft.MintAssetArgs {
+	return &nft.MintAssetArgs{
+		AuthorizedMinter: config.Minter,
+		BaseAsset: &nft.BaseAsset{
+			CollectionName: config.CollectionName,
+			SchemaName:     config.SchemaName,
+			TemplateId:     templateId,
+		},
+		NewAssetOwner: key.Participant,
+		ImmutableData: nft.AttributeMap{
+			"badge":          nft.NewAtomicAttribute("string", key.Badge),
+			"round_id":       nft.NewAtomicAttribute("uint64", round.RoundID),
+			"round_name":     nft.NewAtomicAttribute("string", round.RoundName),
+			"participant":    nft.NewAtomicAttribute("string", string(key.Participant)),
+			"prize":          nft.NewAtomicAttribute("string", prize),
+			"entry_position": nft.NewAtomicAttribute("uint64", position),
+		},
+		MutableData:  nft.AttributeMap{},
+		TokensToBack: []eos.Asset{},
+	}
+}
+
+// BadgeKeyFromData returns false if the immutable data is not the data of a badge
+func BadgeKeyFromData(data nft.AttributeMap) (BadgeKey, bool) {
+	badge, roundID, participant := data["badge"], data["round_id"], data["participant"]
+	if badge == nil || roundID == nil || participant == nil || badge.BaseVariant == nil || roundID.BaseVariant == nil || participant.BaseVariant == nil {
+		return BadgeKey{}, false
+	}
+	id, err := roundID.UInt64()
+	if err != nil {
+		return BadgeKey{}, false
+	}
+	badgeName, ok := badge.Impl.(string)
+	if !ok {
+		return BadgeKey{}, false
+	}
+	participantName, ok := participant.Impl.(string)
+	if !ok {
+		return BadgeKey{}, false
+	}
+	return BadgeKey{RoundID: id, Participant: eos.AN(participantName), Badge: badgeName}, true
+}
+
+// GetMintedBadges scans all the assets of the badge schema, badges that have been burned are not found, use
+// LoadMintedBadges to read them from the ledger
+func GetMintedBadges(nftContract *nft.NFTContract, config *BadgeConfig) (map[BadgeKey]bool, error) {
+	schema, err := nftContract.GetSchemaByName(config.CollectionName, config.SchemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting badge schema: %v, error: %v", config.SchemaName, err)
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("badge schema: %v of collection: %v not found", config.SchemaName, config.CollectionName)
+	}
+	minted := make(map[BadgeKey]bool)
+	filter := &nft.AssetFilter{CollectionName: config.CollectionName, SchemaName: config.SchemaName}
+	err = nftContract.ScanAssets(filter, func(owner eos.AccountName, asset *nft.Asset) error {
+		data, err := asset.GetImmutableData(schema.Format)
+		if err != nil {
+			return fmt.Errorf("failed decoding immutable data of asset: %v, error: %v", asset.AssetId, err)
+		}
+		if key, ok := BadgeKeyFromData(data); ok {
+			minted[key] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return minted, nil
+}
+
+// LoadMintedBadges reads the minted badges from the ledger file of the config. If the ledger does not exist
+// yet, it is backfilled by scanning the assets of the badge schema, from then on burned badges are not minted
+// again. If the ledger has pending badges, the process stopped between writing the pending record and
+// recording the mint, they are resolved by scanning the assets
+func LoadMintedBadges(nftContract *nft.NFTContract, config *BadgeConfig) (map[BadgeKey]bool, error) {
+	if config.LedgerFile != "" {
+		_, err := os.Stat(config.LedgerFile)
+		if err == nil {
+			minted, pending, err := LoadBadgeLedger(config.LedgerFile)
+			if err != nil {
+				return nil, err
+			}
+			if len(pending) == 0 {
+				return minted, nil
+			}
+			scanned, err := GetMintedBadges(nftContract, config)
+			if err != nil {
+				return nil, err
+			}
+			err = ResolvePendingBadges(config.LedgerFile, minted, pending, scanned)
+			if err != nil {
+				return nil, err
+			}
+			return minted, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed checking badge ledger: %v, error: %v", config.LedgerFile, err)
+		}
+	}
+	minted, err := GetMintedBadges(nftContract, config)
+	if err != nil {
+		return nil, err
+	}
+	if config.LedgerFile == "" {
+		return minted, nil
+	}
+	err = AppendBadgeLedger(config.LedgerFile, sortedBadgeKeys(minted)...)
+	if err != nil {
+		return nil, err
+	}
+	return minted, nil
+}
+
+// ResolvePendingBadges adds the pending badges found in scanned to minted and records them in the ledger, the
+// pending badges that were not minted are left out so that they are minted again
+func ResolvePendingBadges(file string, minted, pending, scanned map[BadgeKey]bool) error {
+	resolved := make(map[BadgeKey]bool)
+	for key := range pending {
+		if scanned[key] {
+			resolved[key] = true
+			minted[key] = true
+		}
+	}
+	if len(resolved) == 0 {
+		return nil
+	}
+	return AppendBadgeLedger(file, sortedBadgeKeys(resolved)...)
+}
+
+func sortedBadgeKeys(badges map[BadgeKey]bool) []BadgeKey {
+	keys := make([]BadgeKey, 0, len(badges))
+	for key := range badges {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].RoundID != keys[j].RoundID {
+			return keys[i].RoundID < keys[j].RoundID
+		}
+		if keys[i].Participant != keys[j].Participant {
+			return keys[i].Participant < keys[j].Participant
+		}
+		return keys[i].Badge < keys[j].Badge
+	})
+	return keys
+}
+
+// AppendBadgeLedger appends the minted badges to the ledger file as JSON lines, the file is created if it does
+// not exist
+func AppendBadgeLedger(file string, keys ...BadgeKey) error {
+	return appendBadgeLedger(file, false, keys)
+}
+
+// AppendPendingBadges appends the badges about to be minted to the ledger file
+func AppendPendingBadges(file string, keys ...BadgeKey) error {
+	return appendBadgeLedger(file, true, keys)
+}
+
+func appendBadgeLedger(file string, pending bool, keys []BadgeKey) error {
+	data := make([]byte, 0)
+	for _, key := range keys {
+		line, err := json.Marshal(&BadgeLedgerRecord{BadgeKey: key, Pending: pending})
+		if err != nil {
+			return fmt.Errorf("failed serializing badge key, error: %v", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed opening badge ledger: %v, error: %v", file, err)
+	}
+	defer f.Close()
+	_, err = f.Write(data)
+	if err != nil {
+		return fmt.Errorf("failed writing badge ledger: %v, error: %v", file, err)
+	}
+	return nil
+}
+
+// LoadBadgeLedger reads the minted badges and the badges that are pending, the ones whose last record is a
+// pending record, from the ledger file
+func LoadBadgeLedger(file string) (map[BadgeKey]bool, map[BadgeKey]bool, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed reading badge ledger: %v, error: %v", file, err)
+	}
+	minted := make(map[BadgeKey]bool)
+	pending := make(map[BadgeKey]bool)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for decoder.More() {
+		var record BadgeLedgerRecord
+		err = decoder.Decode(&record)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed parsing badge ledger: %v, error: %v", file, err)
+		}
+		if record.Pending {
+			if !minted[record.BadgeKey] {
+				pending[record.BadgeKey] = true
+			}
+			continue
+		}
+		minted[record.BadgeKey] = true
+		delete(pending, record.BadgeKey)
+	}
+	return minted, pending, nil
+}
+
+// MintRoundBadges mints the missing badges of a closed round, returns the mint args of the minted badges
+func (m *BennyfiContract) MintRoundBadges(nftContract *nft.NFTContract, config *BadgeConfig, roundID uint64) ([]*nft.MintAssetArgs, error) {
+	round, err := m.GetRound(roundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting round: %v, error: %v", roundID, err)
+	}
+	if round == nil {
+		return nil, fmt.Errorf("round: %v not found", roundID)
+	}
+	var entries []Entry
+	if config.MintParticipation {
+		request := &eos.GetTableRowsRequest{Limit: round.NumParticipants}
+		m.FilterEntriesbyRound(request, roundID)
+		entries, err = m.GetEntriesReq(request)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting entries of round: %v, error: %v", roundID, err)
+		}
+	}
+	minted, err := LoadMintedBadges(nftContract, config)
+	if err != nil {
+		return nil, err
+	}
+	return mintBadges(nftContract, config, round, entries, minted)
+}
+
+// BackfillBadges mints the missing badges of every closed round
+func (m *BennyfiContract) BackfillBadges(nftContract *nft.NFTContract, config *BadgeConfig) ([]*nft.MintAssetArgs, error) {
+	rounds, err := m.GetAllRounds()
+	if err != nil {
+		return nil, fmt.Errorf("failed getting rounds, error: %v", err)
+	}
+	var entries []Entry
+	if config.MintParticipation {
+		entries, err = m.GetAllEntries()
+		if err != nil {
+			return nil, fmt.Errorf("failed getting entries, error: %v", err)
+		}
+	}
+	minted, err := LoadMintedBadges(nftContract, config)
+	if err != nil {
+		return nil, err
+	}
+	mints := make([]*nft.MintAssetArgs, 0)
+	for i := range rounds {
+		if !containsName(BadgeRoundStates, rounds[i].CurrentState) {
+			continue
+		}
+		roundMints, err := mintBadges(nftContract, config, &rounds[i], entries, minted)
+		mints = append(mints, roundMints...)
+		if err != nil {
+			return mints, err
+		}
+	}
+	return mints, nil
+}
+
+// mintBadges records each badge in the ledger as pending before minting it and as minted right after, so that
+// a failure part way through does not cause double mints when the process is retried
+func mintBadges(nftContract *nft.NFTContract, config *BadgeConfig, round *Round, entries []Entry, minted map[BadgeKey]bool) ([]*nft.MintAssetArgs, error) {
+	planned, err := PlanRoundBadges(config, round, entries, minted)
+	if err != nil {
+		return nil, err
+	}
+	mints := make([]*nft.MintAssetArgs, 0, len(planned))
+	for _, mint := range planned {
+		key, _ := BadgeKeyFromData(mint.ImmutableData)
+		if config.LedgerFile != "" {
+			err = AppendPendingBadges(config.LedgerFile, key)
+			if err != nil {
+				return mints, err
+			}
+		}
+		_, err = nftContract.MintAsset(mint)
+		if err != nil {
+			return mints, fmt.Errorf("failed minting badge for: %v of round: %v, error: %v", mint.NewAssetOwner, round.RoundID, err)
+		}
+		minted[key] = true
+		mints = append(mints, mint)
+		if config.LedgerFile != "" {
+			err = AppendBadgeLedger(config.LedgerFile, key)
+			if err != nil {
+				return mints, err
+			}
+		}
+	}
+	return mints, nil
+}
@@ -0,0 +1,137 @@
+package bennyfi_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+	"github.com/sebastianmontero/bennyfi-go-client/nft"
+	"gotest.tools/assert"
+)
+
+func badgeConfig(participation bool) *bennyfi.BadgeConfig {
+	return &bennyfi.BadgeConfig{
+		Minter:                  "bennyfi",
+		CollectionName:          "bennyfibadge",
+		SchemaName:              "rounds",
+		WinnerTemplateId:        1,
+		ParticipationTemplateId: -1,
+		MintParticipation:       participation,
+	}
+}
+
+func closedRound() (*bennyfi.Round, []bennyfi.Entry) {
+	round := &bennyfi.Round{
+		RoundID:      3,
+		RoundName:    "round three",
+		CurrentState: bennyfi.RoundClosed,
+		Winners: bennyfi.Winners{
+			bennyfi.NewWinner("alice", "5.0000 TLOS", 2),
+		},
+	}
+	entries := []bennyfi.Entry{
+		{RoundID: 3, Position: 3, Participant: "carol", Prize: "0.0000 TLOS", EntryStatus: bennyfi.EntryStaked},
+		{RoundID: 3, Position: 2, Participant: "alice", Prize: "5.0000 TLOS", EntryStatus: bennyfi.EntryStaked},
+		{RoundID: 3, Position: 1, Participant: "bob", Prize: "0.0000 TLOS", EntryStatus: bennyfi.EntryStaked},
+		{RoundID: 3, Position: 4, Participant: "dave", EntryStatus: bennyfi.EntryEarlyExit},
+		{RoundID: 4, Position: 1, Participant: "erin", EntryStatus: bennyfi.EntryStaked},
+	}
+	return round, entries
+}
+
+func TestPlanRoundBadges(t *testing.T) {
+	round, entries := closedRound()
+	mints, err := bennyfi.PlanRoundBadges(badgeConfig(false), round, entries, map[bennyfi.BadgeKey]bool{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(mints), 1)
+	assert.Equal(t, string(mints[0].NewAssetOwner), "alice")
+	assert.Equal(t, mints[0].TemplateId, int32(1))
+	assert.NilError(t, nft.ValidateData(mints[0].ImmutableData, bennyfi.BadgeFormats))
+	assert.Equal(t, mints[0].ImmutableData["prize"].String(), "5.0000 TLOS")
+	assert.Equal(t, mints[0].ImmutableData["entry_position"].String(), "2")
+
+	mints, err = bennyfi.PlanRoundBadges(badgeConfig(true), round, entries, map[bennyfi.BadgeKey]bool{})
+	assert.NilError(t, err)
+	owners := make([]string, 0)
+	for _, mint := range mints {
+		key, ok := bennyfi.BadgeKeyFromData(mint.ImmutableData)
+		assert.Assert(t, ok)
+		owners = append(owners, string(mint.NewAssetOwner)+":"+key.Badge)
+	}
+	assert.DeepEqual(t, owners, []string{"alice:winner", "bob:participant", "carol:participant"})
+	assert.Equal(t, mints[1].TemplateId, int32(-1))
+}
+
+func TestPlanRoundBadgesIdempotent(t *testing.T) {
+	round, entries := closedRound()
+	minted := map[bennyfi.BadgeKey]bool{
+		{RoundID: 3, Participant: "alice", Badge: bennyfi.BadgeWinner}:    true,
+		{RoundID: 3, Participant: "bob", Badge: bennyfi.BadgeParticipant}: true,
+	}
+	mints, err := bennyfi.PlanRoundBadges(badgeConfig(true), round, entries, minted)
+	assert.NilError(t, err)
+	assert.Equal(t, len(mints), 1)
+	assert.Equal(t, string(mints[0].NewAssetOwner), "carol")
+
+	data, err := nft.SerializeData(mints[0].ImmutableData, bennyfi.BadgeFormats)
+	assert.NilError(t, err)
+	decoded, err := nft.DeserializeData(data, bennyfi.BadgeFormats)
+	assert.NilError(t, err)
+	key, ok := bennyfi.BadgeKeyFromData(decoded)
+	assert.Assert(t, ok)
+	minted[key] = true
+	mints, err = bennyfi.PlanRoundBadges(badgeConfig(true), round, entries, minted)
+	assert.NilError(t, err)
+	assert.Equal(t, len(mints), 0)
+
+	_, ok = bennyfi.BadgeKeyFromData(nft.AttributeMap{"badge": nft.NewAtomicAttribute("string", "winner")})
+	assert.Assert(t, !ok)
+
+	round.CurrentState = bennyfi.RoundOpen
+	_, err = bennyfi.PlanRoundBadges(badgeConfig(true), round, entries, minted)
+	assert.ErrorContains(t, err, "has not been closed")
+}
+
+func TestBadgeLedger(t *testing.T) {
+	config := badgeConfig(true)
+	config.LedgerFile = filepath.Join(t.TempDir(), "badges.jsonl")
+	_, _, err := bennyfi.LoadBadgeLedger(config.LedgerFile)
+	assert.ErrorContains(t, err, "failed reading badge ledger")
+
+	assert.NilError(t, bennyfi.AppendBadgeLedger(config.LedgerFile, bennyfi.BadgeKey{RoundID: 3, Participant: "alice", Badge: bennyfi.BadgeWinner}))
+	assert.NilError(t, bennyfi.AppendBadgeLedger(config.LedgerFile,
+		bennyfi.BadgeKey{RoundID: 3, Participant: "bob", Badge: bennyfi.BadgeParticipant},
+		bennyfi.BadgeKey{RoundID: 3, Participant: "carol", Badge: bennyfi.BadgeParticipant},
+	))
+
+	// the ledger exists so the assets are not scanned, badges burned since they were minted stay minted
+	minted, err := bennyfi.LoadMintedBadges(nil, config)
+	assert.NilError(t, err)
+	assert.Equal(t, len(minted), 3)
+	round, entries := closedRound()
+	mints, err := bennyfi.PlanRoundBadges(config, round, entries, minted)
+	assert.NilError(t, err)
+	assert.Equal(t, len(mints), 0)
+
+	// dave was minted before the process stopped, erin was not
+	dave := bennyfi.BadgeKey{RoundID: 4, Participant: "dave", Badge: bennyfi.BadgeWinner}
+	erin := bennyfi.BadgeKey{RoundID: 4, Participant: "erin", Badge: bennyfi.BadgeParticipant}
+	assert.NilError(t, bennyfi.AppendPendingBadges(config.LedgerFile, dave, erin))
+	minted, pending, err := bennyfi.LoadBadgeLedger(config.LedgerFile)
+	assert.NilError(t, err)
+	assert.Equal(t, len(minted), 3)
+	assert.DeepEqual(t, pending, map[bennyfi.BadgeKey]bool{dave: true, erin: true})
+
+	assert.NilError(t, bennyfi.ResolvePendingBadges(config.LedgerFile, minted, pending, map[bennyfi.BadgeKey]bool{dave: true}))
+	assert.Assert(t, minted[dave] && !minted[erin])
+	minted, pending, err = bennyfi.LoadBadgeLedger(config.LedgerFile)
+	assert.NilError(t, err)
+	assert.Equal(t, len(minted), 4)
+	assert.DeepEqual(t, pending, map[bennyfi.BadgeKey]bool{erin: true})
+
+	assert.NilError(t, bennyfi.AppendBadgeLedger(config.LedgerFile, erin))
+	minted, pending, err = bennyfi.LoadBadgeLedger(config.LedgerFile)
+	assert.NilError(t, err)
+	assert.Equal(t, len(minted), 5)
+	assert.Equal(t, len(pending), 0)
+}
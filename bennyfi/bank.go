@@ -25,8 +25,14 @@ import (
 	"fmt"
 
 	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/internal/tablepage"
+	"github.com/sebastianmontero/eos-go-toolbox/contract"
 )
 
+// DepositMemo is the memo the transfer notification handler of the bennyfi contract expects to credit the
+// transferred quantity to the liquid balance of the sender in the balances table
+const DepositMemo = "deposit"
+
 type Balance struct {
 	ID            uint64          `json:"id"`
 	TokenHolder   eos.AccountName `json:"token_holder"`
@@ -36,26 +42,121 @@ type Balance struct {
 	TokenContract eos.AccountName `json:"token_contract"`
 }
 
+func (m *Balance) GetLiquidBalance() (eos.Asset, error) {
+	liquid, err := eos.NewAssetFromString(m.LiquidBalance)
+	if err != nil {
+		return eos.Asset{}, fmt.Errorf("invalid liquid balance: %v, error: %v", m.LiquidBalance, err)
+	}
+	return liquid, nil
+}
+
+func (m *Balance) GetStakedBalance() (eos.Asset, error) {
+	staked, err := eos.NewAssetFromString(m.StakedBalance)
+	if err != nil {
+		return eos.Asset{}, fmt.Errorf("invalid staked balance: %v, error: %v", m.StakedBalance, err)
+	}
+	return staked, nil
+}
+
+// Deposit funds the bank balance of the from account by transferring the quantity to the bennyfi contract using
+// the token contract registered for the symbol, and confirms that the liquid balance increased accordingly
+func (m *BennyfiContract) Deposit(from eos.AccountName, quantity eos.Asset) (string, error) {
+	policy, err := m.GetTokenPolicy()
+	if err != nil {
+		return "", err
+	}
+	err = policy.ValidateDeposit(quantity)
+	if err != nil {
+		return "", err
+	}
+	authToken := policy.GetToken(quantity.Symbol)
+	before, err := m.getLiquidBalance(from, quantity.Symbol)
+	if err != nil {
+		return "", err
+	}
+	resp, err := contract.NewTokenContract(m.EOS).Transfer(authToken.TokenContract, from, m.ContractName, quantity, DepositMemo)
+	if err != nil {
+		return "", fmt.Errorf("failed transferring deposit: %v from: %v, error: %v", quantity, from, err)
+	}
+	after, err := m.getLiquidBalance(from, quantity.Symbol)
+	if err != nil {
+		return "", err
+	}
+	if after.Amount-before.Amount < quantity.Amount {
+		return "", fmt.Errorf("deposit Tx ID: %v executed but liquid balance of: %v did not increase by: %v, before: %v, after: %v", resp.TransactionID, from, quantity, before, after)
+	}
+	return fmt.Sprintf("Tx ID: %v", resp.TransactionID), nil
+}
+
+func (m *BennyfiContract) getLiquidBalance(tokenHolder eos.AccountName, symbol eos.Symbol) (eos.Asset, error) {
+	balance, err := m.GetBalance(tokenHolder, symbol.String())
+	if err != nil {
+		return eos.Asset{}, fmt.Errorf("failed getting balance of: %v for symbol: %v, error: %v", tokenHolder, symbol, err)
+	}
+	if balance == nil {
+		return eos.Asset{Amount: 0, Symbol: symbol}, nil
+	}
+	return balance.GetLiquidBalance()
+}
+
 func (m *BennyfiContract) Withdraw(from eos.AccountName, quantity eos.Asset) (string, error) {
+	resp, err := m.WithdrawTx(from, quantity)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Tx ID: %v", resp.TransactionID), nil
+}
+
+// WithdrawTx is Withdraw returning the push transaction response
+func (m *BennyfiContract) WithdrawTx(from eos.AccountName, quantity eos.Asset) (*eos.PushTransactionFullResp, error) {
 	actionData := make(map[string]interface{})
 	actionData["from"] = eos.Name(from)
 	actionData["quantity"] = quantity
 
-	return m.ExecAction(from, "withdraw", actionData)
+	return m.Contract.ExecAction(from, "withdraw", actionData)
 }
 
 func (m *BennyfiContract) WithdrawTot(from eos.AccountName, symbol eos.Symbol) (string, error) {
+	resp, err := m.WithdrawTotTx(from, symbol)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Tx ID: %v", resp.TransactionID), nil
+}
+
+// WithdrawTotTx is WithdrawTot returning the push transaction response
+func (m *BennyfiContract) WithdrawTotTx(from eos.AccountName, symbol eos.Symbol) (*eos.PushTransactionFullResp, error) {
 	actionData := make(map[string]interface{})
 	actionData["from"] = eos.Name(from)
 	actionData["symbol"] = symbol.String()
 
-	return m.ExecAction(from, "withdrawtot", actionData)
+	return m.Contract.ExecAction(from, "withdrawtot", actionData)
 }
 
 func (m *BennyfiContract) GetBalances() ([]Balance, error) {
 	return m.GetBalancesReq(nil)
 }
 
+// GetAllBalances pages through the balances table, GetBalances only returns the first page
+func (m *BennyfiContract) GetAllBalances() ([]Balance, error) {
+	balances := make([]Balance, 0)
+	err := tablepage.ReadAll(func(lowerBound string) (int, bool, error) {
+		var page []Balance
+		more, err := tablepage.GetTableRows(m.Contract, eos.GetTableRowsRequest{
+			Table:      "balances",
+			LowerBound: lowerBound,
+		}, &page)
+		balances = append(balances, page...)
+		return len(page), more, err
+	}, func() (string, error) {
+		return tablepage.AfterID(balances[len(balances)-1].ID), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
+
 func (m *BennyfiContract) GetBalancesReq(req *eos.GetTableRowsRequest) ([]Balance, error) {
 
 	var balances []Balance
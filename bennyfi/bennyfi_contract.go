@@ -27,6 +27,9 @@ import (
 
 	eos "github.com/eoscanada/eos-go"
 	"github.com/eoscanada/eos-go/ecc"
+	"github.com/eoscanada/eos-go/system"
+	"github.com/sebastianmontero/bennyfi-go-client/chain"
+	"github.com/sebastianmontero/bennyfi-go-client/rex"
 	"github.com/sebastianmontero/eos-go-toolbox/contract"
 	"github.com/sebastianmontero/eos-go-toolbox/service"
 	"github.com/sebastianmontero/eos-go-toolbox/util"
@@ -36,19 +39,66 @@ var (
 	PercentageAdjustment       = float64(10000000)
 	PAUSED               int64 = 1
 	UNPAUSED             int64 = 0
+	OpenActions                = []eos.ActionName{
+		"timedevents",
+		"timeoutrnds",
+		"mvfrmsavings",
+		"sellrex",
+		"withdrawrex",
+		"unlockrnds",
+		"redraw",
+		"unstakeopen",
+		"ustkulckrnds",
+		"ustktmdrnds",
+	}
 )
 
+// ContractAction holds everything required to execute or propose a bennyfi action
+type ContractAction struct {
+	PermissionLevel interface{}
+	ActionName      eos.ActionName
+	Data            interface{}
+}
+
+func NewContractAction(permissionLevel interface{}, actionName eos.ActionName, data interface{}) *ContractAction {
+	return &ContractAction{
+		PermissionLevel: permissionLevel,
+		ActionName:      actionName,
+		Data:            data,
+	}
+}
+
 type BennyfiContract struct {
 	*contract.Contract
+	Settings SettingsRegistry
+	Chain    chain.Chain
 }
 
-func NewBennyfiContract(eos *service.EOS, contractName string) *BennyfiContract {
+// NewBennyfiContract creates a client for the bennyfi contract deployed on the chain, errors if the chain is
+// not valid
+func NewBennyfiContract(eos *service.EOS, contractName string, chain chain.Chain) (*BennyfiContract, error) {
+	err := chain.Validate()
+	if err != nil {
+		return nil, err
+	}
 	return &BennyfiContract{
-		&contract.Contract{
+		Contract: &contract.Contract{
 			EOS:          eos,
 			ContractName: contractName,
 		},
-	}
+		Settings: DefaultSettingsRegistry(),
+		Chain:    chain,
+	}, nil
+}
+
+// RexContract returns a client for the rex contract of the chain the bennyfi contract is deployed on
+func (m *BennyfiContract) RexContract() (*rex.RexContract, error) {
+	return rex.NewRexContract(m.EOS, m.Chain)
+}
+
+// CrankPermissionLevel returns the permission level used to call the open actions
+func (m *BennyfiContract) CrankPermissionLevel() string {
+	return fmt.Sprintf("%v@%v", m.ContractName, m.Chain.CrankPermission)
 }
 
 func (m *BennyfiContract) ExecAction(permissionLevel interface{}, action string, actionData interface{}) (string, error) {
@@ -67,36 +117,102 @@ func (m *BennyfiContract) ProposeAction(proposerName interface{}, requested []eo
 	return fmt.Sprintf("Proposal Name: %v, Tx ID: %v", resp.ProposalName, resp.PushTransactionFullResp.TransactionID), nil
 }
 
-func (m *BennyfiContract) ConfigureOpenPermission(publicKey *ecc.PublicKey) error {
-	openActions := []string{
-		"timedevents",
-		"timeoutrnds",
-		"mvfrmsavings",
-		"sellrex",
-		"withdrawrex",
-		"unlockrnds",
-		"redraw",
-		"unstakeopen",
-		"ustkulckrnds",
-		"ustktmdrnds",
+// Exec executes the contract action
+func (m *BennyfiContract) Exec(action *ContractAction) (string, error) {
+	return m.ExecAction(action.PermissionLevel, string(action.ActionName), action.Data)
+}
+
+func (m *BennyfiContract) BuildAction(action *ContractAction) (*eos.Action, error) {
+	return m.EOS.BuildAction(m.ContractName, action.ActionName, action.PermissionLevel, action.Data, 5)
+}
+
+// Propose creates a single multisig proposal containing all the contract actions
+func (m *BennyfiContract) Propose(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, actions ...*ContractAction) (string, error) {
+	eosActions := make([]*eos.Action, 0, len(actions))
+	for _, action := range actions {
+		eosAction, err := m.BuildAction(action)
+		if err != nil {
+			return "", fmt.Errorf("failed proposing multisig action, error building action: %v, error: %v", action.ActionName, err)
+		}
+		eosActions = append(eosActions, eosAction)
+	}
+	return m.ProposeEOSActions(proposerName, requested, expireIn, eosActions...)
+}
+
+func (m *BennyfiContract) ProposeEOSActions(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, actions ...*eos.Action) (string, error) {
+	if len(actions) == 0 {
+		return "", fmt.Errorf("failed proposing multisig, no actions specified")
+	}
+	resp, err := m.EOS.ProposeMultiSig(proposerName, requested, expireIn, actions...)
+	if err != nil {
+		return "", err
 	}
-	err := m.EOS.CreateSimplePermission(m.ContractName, "open", publicKey)
+	return fmt.Sprintf("Proposal Name: %v, Tx ID: %v", resp.ProposalName, resp.PushTransactionFullResp.TransactionID), nil
+}
+
+func (m *BennyfiContract) ConfigureOpenPermission(publicKey *ecc.PublicKey) error {
+	err := m.EOS.CreateSimplePermission(m.ContractName, string(m.Chain.CrankPermission), publicKey)
 	if err != nil {
-		return fmt.Errorf("failed to create open permission, error: %v", err)
+		return fmt.Errorf("failed to create %v permission, error: %v", m.Chain.CrankPermission, err)
 	}
-	for _, action := range openActions {
-		err = m.EOS.LinkPermission(m.ContractName, action, "open", false)
+	for _, action := range OpenActions {
+		err = m.EOS.LinkPermission(m.ContractName, action, string(m.Chain.CrankPermission), false)
 		if err != nil {
-			return fmt.Errorf("failed to link open permission to the %v action, error: %v", action, err)
+			return fmt.Errorf("failed to link %v permission to the %v action, error: %v", m.Chain.CrankPermission, action, err)
 		}
 	}
 	return nil
 }
 
+// ProposeConfigureOpenPermission proposes the creation of the open permission and its link to the open actions
+// in a single multisig proposal
+func (m *BennyfiContract) ProposeConfigureOpenPermission(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, publicKey *ecc.PublicKey) (string, error) {
+	return m.ProposeEOSActions(proposerName, requested, expireIn, m.ConfigureOpenPermissionActions(publicKey)...)
+}
+
+func (m *BennyfiContract) ConfigureOpenPermissionActions(publicKey *ecc.PublicKey) []*eos.Action {
+	account := eos.AN(m.ContractName)
+	active := eos.PermissionName("active")
+	open := m.Chain.CrankPermission
+	actions := []*eos.Action{
+		system.NewUpdateAuth(
+			account,
+			open,
+			active,
+			eos.Authority{
+				Threshold: 1,
+				Keys: []eos.KeyWeight{{
+					PublicKey: *publicKey,
+					Weight:    1,
+				}},
+				Accounts: []eos.PermissionLevelWeight{{
+					Permission: eos.PermissionLevel{
+						Actor:      account,
+						Permission: active,
+					},
+					Weight: 1,
+				}},
+				Waits: []eos.WaitWeight{},
+			}, active),
+	}
+	for _, action := range OpenActions {
+		actions = append(actions, system.NewLinkAuth(account, account, action, open))
+	}
+	return actions
+}
+
 func (m *BennyfiContract) Pause(pause int64) (string, error) {
+	return m.Exec(m.PauseAction(pause))
+}
+
+func (m *BennyfiContract) ProposePause(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, pause int64) (string, error) {
+	return m.Propose(proposerName, requested, expireIn, m.PauseAction(pause))
+}
+
+func (m *BennyfiContract) PauseAction(pause int64) *ContractAction {
 	actionData := make(map[string]interface{})
 	actionData["pause"] = pause
-	return m.ExecAction(eos.AN(m.ContractName), "pause", actionData)
+	return NewContractAction(eos.AN(m.ContractName), "pause", actionData)
 }
 
 func CalculatePercentage(amount interface{}, percentage int64) (eos.Asset, error) {
@@ -26,6 +26,7 @@ import (
 	"strconv"
 
 	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/internal/tablepage"
 )
 
 var (
@@ -71,7 +72,7 @@ func (m *BennyfiContract) Unstake(entryId uint64, permissionLevel interface{}) (
 func (m *BennyfiContract) UnstakeOpen(entryId uint64) (string, error) {
 	actionData := make(map[string]interface{})
 	actionData["entry_id"] = entryId
-	return m.ExecAction(fmt.Sprintf("%v@open", m.ContractName), "unstakeopen", actionData)
+	return m.ExecAction(m.CrankPermissionLevel(), "unstakeopen", actionData)
 }
 
 func (m *BennyfiContract) GetEntries() ([]Entry, error) {
@@ -79,12 +80,48 @@ func (m *BennyfiContract) GetEntries() ([]Entry, error) {
 	return m.GetEntriesReq(&eos.GetTableRowsRequest{})
 }
 
+// GetAllEntries pages through the entries table, GetEntries only returns the first page
+func (m *BennyfiContract) GetAllEntries() ([]Entry, error) {
+	entries := make([]Entry, 0)
+	err := tablepage.ReadAll(func(lowerBound string) (int, bool, error) {
+		var page []Entry
+		more, err := tablepage.GetTableRows(m.Contract, eos.GetTableRowsRequest{
+			Table:      "entries",
+			LowerBound: lowerBound,
+		}, &page)
+		entries = append(entries, page...)
+		return len(page), more, err
+	}, func() (string, error) {
+		return tablepage.AfterID(entries[len(entries)-1].EntryID), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
 func (m *BennyfiContract) GetEntriesbyParticipant(participant eos.AccountName) ([]Entry, error) {
 	request := &eos.GetTableRowsRequest{}
 	m.FilterEntriesbyParticipant(request, participant)
 	return m.GetEntriesReq(request)
 }
 
+// GetAllEntriesbyParticipant reads all the entries of the participant, the participant index can not be paged
+// by lower bound since all its keys are the same, so the whole table is paged and filtered
+func (m *BennyfiContract) GetAllEntriesbyParticipant(participant eos.AccountName) ([]Entry, error) {
+	entries, err := m.GetAllEntries()
+	if err != nil {
+		return nil, err
+	}
+	participantEntries := make([]Entry, 0)
+	for _, entry := range entries {
+		if entry.Participant == participant {
+			participantEntries = append(participantEntries, entry)
+		}
+	}
+	return participantEntries, nil
+}
+
 func (m *BennyfiContract) FilterEntriesbyParticipant(req *eos.GetTableRowsRequest, participant eos.AccountName) {
 	req.Index = "4"
 	req.KeyType = "name"
@@ -0,0 +1,209 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package bennyfi
+
+import (
+	"fmt"
+	"sort"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/nft"
+)
+
+// NFTGate restricts round entry to the holders of at least one asset that matches any of the filters
+type NFTGate struct {
+	Filters []*nft.AssetFilter
+}
+
+func NewNFTGate(filters ...*nft.AssetFilter) *NFTGate {
+	return &NFTGate{
+		Filters: filters,
+	}
+}
+
+func (m *NFTGate) Matches(asset *nft.Asset) bool {
+	for _, filter := range m.Filters {
+		if filter.Matches(asset) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEligible returns true if the account holds a qualifying asset
+func (m *NFTGate) IsEligible(nftContract *nft.NFTContract, account eos.AccountName) (bool, error) {
+	assets, err := nftContract.GetAllAssets(account)
+	if err != nil {
+		return false, fmt.Errorf("failed getting assets of: %v, error: %v", account, err)
+	}
+	for i := range assets {
+		if m.Matches(&assets[i]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Holders returns the sorted accounts that hold a qualifying asset, scanning all the owners of the nft contract
+func (m *NFTGate) Holders(nftContract *nft.NFTContract) ([]eos.AccountName, error) {
+	if len(m.Filters) == 0 {
+		return make([]eos.AccountName, 0), nil
+	}
+	var filter *nft.AssetFilter
+	if len(m.Filters) == 1 {
+		filter = m.Filters[0]
+	}
+	found := make(map[eos.AccountName]bool)
+	err := nftContract.ScanAssets(filter, func(owner eos.AccountName, asset *nft.Asset) error {
+		if m.Matches(asset) {
+			found[owner] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	holders := make([]eos.AccountName, 0, len(found))
+	for holder := range found {
+		holders = append(holders, holder)
+	}
+	sort.Slice(holders, func(i, j int) bool {
+		return holders[i] < holders[j]
+	})
+	return holders, nil
+}
+
+// EnterGatedRound checks that the participant holds a qualifying asset before entering the round
+func (m *BennyfiContract) EnterGatedRound(nftContract *nft.NFTContract, gate *NFTGate, roundId uint64, participant eos.AccountName) (string, error) {
+	eligible, err := gate.IsEligible(nftContract, participant)
+	if err != nil {
+		return "", err
+	}
+	if !eligible {
+		return "", fmt.Errorf("participant: %v does not hold a qualifying nft to enter round: %v", participant, roundId)
+	}
+	return m.EnterRound(roundId, participant)
+}
+
+// PlayerAuthSyncPlan lists the accounts that are granted and revoked the Player auth level
+type PlayerAuthSyncPlan struct {
+	Enroller eos.AccountName
+	Notes    string
+	Grant    []eos.AccountName
+	Revoke   []eos.AccountName
+}
+
+func (m *PlayerAuthSyncPlan) IsEmpty() bool {
+	return len(m.Grant) == 0 && len(m.Revoke) == 0
+}
+
+// Actions returns the setauthlevel actions of the grants followed by the eraseauth actions of the revocations
+func (m *PlayerAuthSyncPlan) Actions(contract *BennyfiContract) []*ContractAction {
+	actions := make([]*ContractAction, 0, len(m.Grant)+len(m.Revoke))
+	for _, account := range m.Grant {
+		actions = append(actions, contract.SetAuthLevelAction(m.Enroller, account, Player, m.Notes))
+	}
+	for _, account := range m.Revoke {
+		actions = append(actions, contract.EraseAuthAction(m.Enroller, account))
+	}
+	return actions
+}
+
+// PlanPlayerAuthSync grants the Player auth level to the holders that have no auth, and revokes the Player auths
+// that were granted by the enroller with the same notes from the accounts that are no longer holders. Auths of
+// other levels and Player auths granted by other means are never changed
+func PlanPlayerAuthSync(enroller eos.AccountName, notes string, holders []eos.AccountName, auths []Auth) *PlayerAuthSyncPlan {
+	plan := &PlayerAuthSyncPlan{
+		Enroller: enroller,
+		Notes:    notes,
+		Grant:    make([]eos.AccountName, 0),
+		Revoke:   make([]eos.AccountName, 0),
+	}
+	isHolder := make(map[eos.AccountName]bool, len(holders))
+	for _, holder := range holders {
+		isHolder[holder] = true
+	}
+	authsByAccount := make(map[eos.AccountName]*Auth, len(auths))
+	for i := range auths {
+		authsByAccount[auths[i].Account] = &auths[i]
+	}
+	for holder := range isHolder {
+		if _, ok := authsByAccount[holder]; ok {
+			continue
+		}
+		plan.Grant = append(plan.Grant, holder)
+	}
+	for _, auth := range auths {
+		if auth.Level == Player && auth.Authorizer == enroller && auth.Notes == notes && !isHolder[auth.Account] {
+			plan.Revoke = append(plan.Revoke, auth.Account)
+		}
+	}
+	sortAccounts(plan.Grant)
+	sortAccounts(plan.Revoke)
+	return plan
+}
+
+func sortAccounts(accounts []eos.AccountName) {
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i] < accounts[j]
+	})
+}
+
+// PlanNFTPlayerAuths plans the sync of the Player auths with the current holders of the gate
+func (m *BennyfiContract) PlanNFTPlayerAuths(nftContract *nft.NFTContract, gate *NFTGate, enroller eos.AccountName, notes string) (*PlayerAuthSyncPlan, error) {
+	holders, err := gate.Holders(nftContract)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting nft holders, error: %v", err)
+	}
+	auths, err := m.GetAllAuths()
+	if err != nil {
+		return nil, fmt.Errorf("failed getting auths, error: %v", err)
+	}
+	return PlanPlayerAuthSync(enroller, notes, holders, auths), nil
+}
+
+// ApplyPlayerAuthSyncPlan executes the grants followed by the revocations, stopping at the first error
+func (m *BennyfiContract) ApplyPlayerAuthSyncPlan(plan *PlayerAuthSyncPlan) error {
+	for _, account := range plan.Grant {
+		_, err := m.Exec(m.SetAuthLevelAction(plan.Enroller, account, Player, plan.Notes))
+		if err != nil {
+			return fmt.Errorf("failed granting player auth to: %v, error: %v", account, err)
+		}
+	}
+	for _, account := range plan.Revoke {
+		_, err := m.Exec(m.EraseAuthAction(plan.Enroller, account))
+		if err != nil {
+			return fmt.Errorf("failed revoking player auth from: %v, error: %v", account, err)
+		}
+	}
+	return nil
+}
+
+// SyncNFTPlayerAuths grants the Player auth level to the current holders of the gate and revokes it from the
+// accounts that no longer hold a qualifying asset, returns the executed plan
+func (m *BennyfiContract) SyncNFTPlayerAuths(nftContract *nft.NFTContract, gate *NFTGate, enroller eos.AccountName, notes string) (*PlayerAuthSyncPlan, error) {
+	plan, err := m.PlanNFTPlayerAuths(nftContract, gate, enroller, notes)
+	if err != nil {
+		return nil, err
+	}
+	return plan, m.ApplyPlayerAuthSyncPlan(plan)
+}
@@ -0,0 +1,51 @@
+package bennyfi_test
+
+import (
+	"testing"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+	"github.com/sebastianmontero/bennyfi-go-client/nft"
+	"gotest.tools/assert"
+)
+
+func TestNFTGateMatches(t *testing.T) {
+	gate := bennyfi.NewNFTGate(
+		&nft.AssetFilter{CollectionName: "bennyfibadge", TemplateId: 1},
+		&nft.AssetFilter{CollectionName: "partners", SchemaName: "vip"},
+	)
+	assert.Assert(t, gate.Matches(&nft.Asset{BaseAsset: &nft.BaseAsset{CollectionName: "bennyfibadge", SchemaName: "rounds", TemplateId: 1}}))
+	assert.Assert(t, !gate.Matches(&nft.Asset{BaseAsset: &nft.BaseAsset{CollectionName: "bennyfibadge", SchemaName: "rounds", TemplateId: 2}}))
+	assert.Assert(t, gate.Matches(&nft.Asset{BaseAsset: &nft.BaseAsset{CollectionName: "partners", SchemaName: "vip", TemplateId: -1}}))
+	assert.Assert(t, !bennyfi.NewNFTGate().Matches(&nft.Asset{BaseAsset: &nft.BaseAsset{CollectionName: "partners"}}))
+}
+
+func TestPlanPlayerAuthSync(t *testing.T) {
+	notes := "nft gate"
+	auths := []bennyfi.Auth{
+		{Authorizer: "enroller", Account: "alice", Level: bennyfi.Player, Notes: notes},
+		{Authorizer: "enroller", Account: "bob", Level: bennyfi.Player, Notes: notes},
+		{Authorizer: "enroller", Account: "carol", Level: bennyfi.Player, Notes: "manual"},
+		{Authorizer: "other", Account: "dave", Level: bennyfi.Player, Notes: notes},
+		{Authorizer: "enroller", Account: "erin", Level: bennyfi.RoundManager, Notes: notes},
+		{Authorizer: "enroller", Account: "frank", Level: bennyfi.Beneficiary, Notes: notes},
+	}
+	holders := []eos.AccountName{"alice", "erin", "frank", "gina", "gina"}
+	plan := bennyfi.PlanPlayerAuthSync("enroller", notes, holders, auths)
+	assert.DeepEqual(t, plan.Grant, []eos.AccountName{"gina"})
+	assert.DeepEqual(t, plan.Revoke, []eos.AccountName{"bob"})
+	assert.Assert(t, !plan.IsEmpty())
+
+	actions := plan.Actions(newTestContract(t))
+	assert.Equal(t, len(actions), 2)
+	assert.Equal(t, actions[0].ActionName, eos.ActionName("setauthlevel"))
+	assert.Equal(t, actions[0].Data.(map[string]interface{})["auth_level"], bennyfi.Player)
+	assert.Equal(t, actions[1].ActionName, eos.ActionName("eraseauth"))
+
+	plan = bennyfi.PlanPlayerAuthSync("enroller", notes, []eos.AccountName{"alice", "bob"}, auths[:2])
+	assert.Assert(t, plan.IsEmpty())
+
+	// a beneficiary that stops holding is not revoked, as the enroller only erases player auths
+	plan = bennyfi.PlanPlayerAuthSync("enroller", notes, []eos.AccountName{}, auths[5:])
+	assert.Assert(t, plan.IsEmpty())
+}
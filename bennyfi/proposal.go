@@ -0,0 +1,387 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package bennyfi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/eoscanada/eos-go/msig"
+	"github.com/sebastianmontero/bennyfi-go-client/internal/tablepage"
+)
+
+var (
+	MsigContract = eos.AN("eosio.msig")
+)
+
+type ProposalAction struct {
+	Account       eos.AccountName       `json:"account"`
+	Name          eos.ActionName        `json:"name"`
+	Authorization []eos.PermissionLevel `json:"authorization"`
+	// Data holds the bennyfi action type returned by NewActionArgs for actions on the bennyfi contract,
+	// a map with the ABI decoded data for the rest, nil if the data could not be decoded
+	Data interface{} `json:"data"`
+	// HexData and DecodeError are set when the data could not be decoded
+	HexData     eos.HexBytes `json:"hex_data,omitempty"`
+	DecodeError string       `json:"decode_error,omitempty"`
+}
+
+type Proposal struct {
+	Proposer           eos.AccountName       `json:"proposer"`
+	ProposalName       eos.Name              `json:"proposal_name"`
+	Expiration         eos.JSONTime          `json:"expiration"`
+	Actions            []*ProposalAction     `json:"actions"`
+	RequestedApprovals []eos.PermissionLevel `json:"requested_approvals"`
+	ProvidedApprovals  []eos.PermissionLevel `json:"provided_approvals"`
+}
+
+// MissingApprovals returns the requested approvals that have not been provided yet, eosio.msig moves
+// an approval from requested to provided when it is given and back on unapprove, so the requested
+// approvals are exactly the missing ones
+func (m *Proposal) MissingApprovals() []eos.PermissionLevel {
+	return m.RequestedApprovals
+}
+
+func (m *Proposal) IsExpired(now time.Time) bool {
+	return !m.Expiration.Time.After(now)
+}
+
+func (m *Proposal) TouchesContract(contract eos.AccountName) bool {
+	for _, action := range m.Actions {
+		if action.Account == contract {
+			return true
+		}
+	}
+	return false
+}
+
+type proposalRow struct {
+	ProposalName      eos.Name     `json:"proposal_name"`
+	PackedTransaction eos.HexBytes `json:"packed_transaction"`
+}
+
+type approval struct {
+	Level eos.PermissionLevel `json:"level"`
+}
+
+type approvalsRow struct {
+	ProposalName       eos.Name   `json:"proposal_name"`
+	RequestedApprovals []approval `json:"requested_approvals"`
+	ProvidedApprovals  []approval `json:"provided_approvals"`
+}
+
+type oldApprovalsRow struct {
+	ProposalName       eos.Name              `json:"proposal_name"`
+	RequestedApprovals []eos.PermissionLevel `json:"requested_approvals"`
+	ProvidedApprovals  []eos.PermissionLevel `json:"provided_approvals"`
+}
+
+type tableScope struct {
+	Scope eos.Name `json:"scope"`
+}
+
+// GetOpenProposals returns the proposals of all proposers that have not expired and contain at least one
+// action on the bennyfi contract
+func (m *BennyfiContract) GetOpenProposals() ([]*Proposal, error) {
+	proposers, err := m.getProposers()
+	if err != nil {
+		return nil, err
+	}
+	getABI := m.cachedABIGetter()
+	now := time.Now().UTC()
+	proposals := make([]*Proposal, 0)
+	for _, proposer := range proposers {
+		proposerProposals, err := m.getProposals(proposer, getABI)
+		if err != nil {
+			return nil, err
+		}
+		for _, proposal := range proposerProposals {
+			if !proposal.IsExpired(now) && proposal.TouchesContract(eos.AN(m.ContractName)) {
+				proposals = append(proposals, proposal)
+			}
+		}
+	}
+	return proposals, nil
+}
+
+func (m *BennyfiContract) getProposers() ([]eos.AccountName, error) {
+	proposers := make([]eos.AccountName, 0)
+	request := eos.GetTableByScopeRequest{
+		Code:  string(MsigContract),
+		Table: "proposal",
+		Limit: tablepage.Size,
+	}
+	for {
+		resp, err := m.EOS.API.GetTableByScope(context.Background(), request)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting proposal scopes, error: %v", err)
+		}
+		var scopes []tableScope
+		err = json.Unmarshal(resp.Rows, &scopes)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing proposal scopes, error: %v", err)
+		}
+		for _, scope := range scopes {
+			proposers = append(proposers, eos.AN(string(scope.Scope)))
+		}
+		if resp.More == "" {
+			return proposers, nil
+		}
+		request.LowerBound = resp.More
+	}
+}
+
+// GetProposals returns the proposals of the proposer with their actions decoded
+func (m *BennyfiContract) GetProposals(proposer eos.AccountName) ([]*Proposal, error) {
+	return m.getProposals(proposer, m.cachedABIGetter())
+}
+
+func (m *BennyfiContract) getProposals(proposer eos.AccountName, getABI func(eos.AccountName) (*eos.ABI, error)) ([]*Proposal, error) {
+	rows := make([]proposalRow, 0)
+	err := tablepage.ReadAll(func(lowerBound string) (int, bool, error) {
+		var page []proposalRow
+		more, err := tablepage.GetTableRows(m.Contract, eos.GetTableRowsRequest{
+			Code:       string(MsigContract),
+			Scope:      string(proposer),
+			Table:      "proposal",
+			LowerBound: lowerBound,
+		}, &page)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed getting proposals for proposer: %v, error: %v", proposer, err)
+		}
+		rows = append(rows, page...)
+		return len(page), more, nil
+	}, func() (string, error) {
+		return tablepage.AfterName(string(rows[len(rows)-1].ProposalName)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	approvals, err := m.getApprovals(proposer)
+	if err != nil {
+		return nil, err
+	}
+	proposals := make([]*Proposal, 0, len(rows))
+	for _, row := range rows {
+		proposal, err := DecodeProposal(eos.AN(m.ContractName), proposer, row.ProposalName, row.PackedTransaction, getABI)
+		if err != nil {
+			return nil, err
+		}
+		if approval, ok := approvals[row.ProposalName]; ok {
+			proposal.RequestedApprovals = approval.RequestedApprovals
+			proposal.ProvidedApprovals = approval.ProvidedApprovals
+		}
+		proposals = append(proposals, proposal)
+	}
+	return proposals, nil
+}
+
+func (m *BennyfiContract) GetProposal(proposer eos.AccountName, proposalName eos.Name) (*Proposal, error) {
+	proposals, err := m.GetProposals(proposer)
+	if err != nil {
+		return nil, err
+	}
+	for _, proposal := range proposals {
+		if proposal.ProposalName == proposalName {
+			return proposal, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *BennyfiContract) getApprovals(proposer eos.AccountName) (map[eos.Name]*oldApprovalsRow, error) {
+	approvals := make(map[eos.Name]*oldApprovalsRow)
+	var last eos.Name
+	err := tablepage.ReadAll(func(lowerBound string) (int, bool, error) {
+		var rows []approvalsRow
+		more, err := tablepage.GetTableRows(m.Contract, eos.GetTableRowsRequest{
+			Code:       string(MsigContract),
+			Scope:      string(proposer),
+			Table:      "approvals2",
+			LowerBound: lowerBound,
+		}, &rows)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed getting approvals for proposer: %v, error: %v", proposer, err)
+		}
+		for _, row := range rows {
+			approvals[row.ProposalName] = &oldApprovalsRow{
+				ProposalName:       row.ProposalName,
+				RequestedApprovals: toPermissionLevels(row.RequestedApprovals),
+				ProvidedApprovals:  toPermissionLevels(row.ProvidedApprovals),
+			}
+			last = row.ProposalName
+		}
+		return len(rows), more, nil
+	}, func() (string, error) {
+		return tablepage.AfterName(string(last)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = tablepage.ReadAll(func(lowerBound string) (int, bool, error) {
+		var oldRows []oldApprovalsRow
+		more, err := tablepage.GetTableRows(m.Contract, eos.GetTableRowsRequest{
+			Code:       string(MsigContract),
+			Scope:      string(proposer),
+			Table:      "approvals",
+			LowerBound: lowerBound,
+		}, &oldRows)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed getting old approvals for proposer: %v, error: %v", proposer, err)
+		}
+		for i := range oldRows {
+			if _, ok := approvals[oldRows[i].ProposalName]; !ok {
+				approvals[oldRows[i].ProposalName] = &oldRows[i]
+			}
+			last = oldRows[i].ProposalName
+		}
+		return len(oldRows), more, nil
+	}, func() (string, error) {
+		return tablepage.AfterName(string(last)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+func toPermissionLevels(approvals []approval) []eos.PermissionLevel {
+	levels := make([]eos.PermissionLevel, 0, len(approvals))
+	for _, approval := range approvals {
+		levels = append(levels, approval.Level)
+	}
+	return levels
+}
+
+func (m *BennyfiContract) cachedABIGetter() func(eos.AccountName) (*eos.ABI, error) {
+	abis := make(map[eos.AccountName]*eos.ABI)
+	return func(account eos.AccountName) (*eos.ABI, error) {
+		if abi, ok := abis[account]; ok {
+			return abi, nil
+		}
+		resp, err := m.EOS.API.GetABI(context.Background(), account)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting abi for account: %v, error: %v", account, err)
+		}
+		abis[account] = &resp.ABI
+		return &resp.ABI, nil
+	}
+}
+
+// DecodeProposal decodes the packed transaction of a proposal, getABI provides the abi of each of the
+// action accounts, the data of the contract actions is decoded into the types returned by NewActionArgs.
+// Actions whose data can not be decoded keep their raw data and the decode error
+func DecodeProposal(contract, proposer eos.AccountName, proposalName eos.Name, packedTrx eos.HexBytes, getABI func(eos.AccountName) (*eos.ABI, error)) (*Proposal, error) {
+	var trx eos.Transaction
+	err := eos.NewDecoder(packedTrx).Decode(&trx)
+	if err != nil {
+		return nil, fmt.Errorf("failed decoding transaction of proposal: %v, error: %v", proposalName, err)
+	}
+	proposal := &Proposal{
+		Proposer:     proposer,
+		ProposalName: proposalName,
+		Expiration:   trx.Expiration,
+		Actions:      make([]*ProposalAction, 0, len(trx.Actions)),
+	}
+	for _, action := range trx.Actions {
+		abi, err := getABI(action.Account)
+		if err != nil {
+			return nil, err
+		}
+		proposalAction := &ProposalAction{
+			Account:       action.Account,
+			Name:          action.Name,
+			Authorization: action.Authorization,
+		}
+		proposalAction.Data, err = decodeActionData(contract, action, abi)
+		if err != nil {
+			proposalAction.Data = nil
+			proposalAction.HexData = action.HexData
+			proposalAction.DecodeError = err.Error()
+		}
+		proposal.Actions = append(proposal.Actions, proposalAction)
+	}
+	return proposal, nil
+}
+
+func decodeActionData(contract eos.AccountName, action *eos.Action, abi *eos.ABI) (interface{}, error) {
+	jsonData, err := abi.DecodeAction(action.HexData, action.Name)
+	if err != nil {
+		return nil, err
+	}
+	var data interface{}
+	if action.Account == contract {
+		data = NewActionArgs(action.Name)
+	}
+	if data == nil {
+		dataMap := make(map[string]interface{})
+		err = decodeJSON(jsonData, &dataMap)
+		return dataMap, err
+	}
+	return data, decodeJSON(jsonData, data)
+}
+
+func decodeJSON(jsonData []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+	err := decoder.Decode(v)
+	if err != nil {
+		return fmt.Errorf("failed parsing action data: %v, error: %v", string(jsonData), err)
+	}
+	return nil
+}
+
+func (m *BennyfiContract) ApproveProposal(proposer eos.AccountName, proposalName eos.Name, level eos.PermissionLevel) (string, error) {
+	resp, err := m.EOS.ApproveMultiSig(proposer, proposalName, level)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Tx ID: %v", resp.TransactionID), nil
+}
+
+func (m *BennyfiContract) UnapproveProposal(proposer eos.AccountName, proposalName eos.Name, level eos.PermissionLevel) (string, error) {
+	resp, err := m.EOS.Trx(5, msig.NewUnapprove(proposer, proposalName, level))
+	if err != nil {
+		return "", fmt.Errorf("failed pushing unapprove transaction, error: %v", err)
+	}
+	return fmt.Sprintf("Tx ID: %v", resp.TransactionID), nil
+}
+
+func (m *BennyfiContract) ExecuteProposal(proposer eos.AccountName, proposalName eos.Name, executer eos.AccountName) (string, error) {
+	resp, err := m.EOS.ExecuteMultiSig(proposer, proposalName, executer)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Tx ID: %v", resp.TransactionID), nil
+}
+
+func (m *BennyfiContract) CancelProposal(proposer eos.AccountName, proposalName eos.Name, canceler eos.AccountName) (string, error) {
+	resp, err := m.EOS.Trx(5, msig.NewCancel(proposer, proposalName, canceler))
+	if err != nil {
+		return "", fmt.Errorf("failed pushing cancel transaction, error: %v", err)
+	}
+	return fmt.Sprintf("Tx ID: %v", resp.TransactionID), nil
+}
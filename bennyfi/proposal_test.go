@@ -0,0 +1,144 @@
+package bennyfi_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+	"gotest.tools/assert"
+)
+
+const bennyfiTestABI = `{
+	"version": "eosio::abi/1.1",
+	"structs": [{
+		"name": "setauthlevel", "base": "",
+		"fields": [
+			{"name": "authorizer", "type": "name"},
+			{"name": "account", "type": "name"},
+			{"name": "auth_level", "type": "uint64"},
+			{"name": "notes", "type": "string"}
+		]
+	}, {
+		"name": "unknownact", "base": "",
+		"fields": [{"name": "value", "type": "uint32"}]
+	}],
+	"actions": [
+		{"name": "setauthlevel", "type": "setauthlevel", "ricardian_contract": ""},
+		{"name": "unknownact", "type": "unknownact", "ricardian_contract": ""}
+	]
+}`
+
+const tokenTestABI = `{
+	"version": "eosio::abi/1.1",
+	"structs": [{
+		"name": "transfer", "base": "",
+		"fields": [
+			{"name": "from", "type": "name"},
+			{"name": "to", "type": "name"},
+			{"name": "quantity", "type": "asset"},
+			{"name": "memo", "type": "string"}
+		]
+	}],
+	"actions": [{"name": "transfer", "type": "transfer", "ricardian_contract": ""}]
+}`
+
+func newTestABI(t *testing.T, abiJSON string) *eos.ABI {
+	abi, err := eos.NewABI(strings.NewReader(abiJSON))
+	assert.NilError(t, err)
+	return abi
+}
+
+func newProposalAction(t *testing.T, abi *eos.ABI, account eos.AccountName, name eos.ActionName, data string) *eos.Action {
+	hexData, err := abi.EncodeAction(name, []byte(data))
+	assert.NilError(t, err)
+	return &eos.Action{
+		Account:       account,
+		Name:          name,
+		Authorization: []eos.PermissionLevel{{Actor: "alice", Permission: "active"}},
+		ActionData:    eos.NewActionDataFromHexData(hexData),
+	}
+}
+
+func TestDecodeProposal(t *testing.T) {
+	abis := map[eos.AccountName]*eos.ABI{
+		"bennyfi":     newTestABI(t, bennyfiTestABI),
+		"eosio.token": newTestABI(t, tokenTestABI),
+	}
+	getABI := func(account eos.AccountName) (*eos.ABI, error) {
+		if abi, ok := abis[account]; ok {
+			return abi, nil
+		}
+		return nil, fmt.Errorf("no abi for account: %v", account)
+	}
+	trx := &eos.Transaction{
+		Actions: []*eos.Action{
+			newProposalAction(t, abis["bennyfi"], "bennyfi", "setauthlevel", `{"authorizer":"alice","account":"bob","auth_level":20,"notes":"enroller"}`),
+			newProposalAction(t, abis["bennyfi"], "bennyfi", "unknownact", `{"value":7}`),
+			newProposalAction(t, abis["eosio.token"], "eosio.token", "transfer", `{"from":"alice","to":"bob","quantity":"1.0000 TLOS","memo":"hi"}`),
+		},
+	}
+	packed, err := eos.MarshalBinary(trx)
+	assert.NilError(t, err)
+
+	proposal, err := bennyfi.DecodeProposal("bennyfi", "alice", "prop1", packed, getABI)
+	assert.NilError(t, err)
+	assert.Equal(t, proposal.Proposer, eos.AN("alice"))
+	assert.Equal(t, proposal.ProposalName, eos.Name("prop1"))
+	assert.Equal(t, len(proposal.Actions), 3)
+	assert.Assert(t, proposal.TouchesContract("bennyfi"))
+	assert.Assert(t, !proposal.TouchesContract("other"))
+
+	assert.DeepEqual(t, proposal.Actions[0].Data, &bennyfi.SetAuthLevelArgs{
+		Authorizer: "alice",
+		Account:    "bob",
+		Level:      20,
+		Notes:      "enroller",
+	})
+	assert.DeepEqual(t, proposal.Actions[0].Authorization, []eos.PermissionLevel{{Actor: "alice", Permission: "active"}})
+	unknown, ok := proposal.Actions[1].Data.(map[string]interface{})
+	assert.Assert(t, ok)
+	assert.Equal(t, fmt.Sprint(unknown["value"]), "7")
+	transfer, ok := proposal.Actions[2].Data.(map[string]interface{})
+	assert.Assert(t, ok)
+	assert.Equal(t, transfer["quantity"], "1.0000 TLOS")
+	assert.Equal(t, transfer["memo"], "hi")
+
+	_, err = bennyfi.DecodeProposal("bennyfi", "alice", "prop1", packed, func(account eos.AccountName) (*eos.ABI, error) {
+		return nil, fmt.Errorf("no abi for account: %v", account)
+	})
+	assert.ErrorContains(t, err, "no abi for account: bennyfi")
+
+	broken := newProposalAction(t, abis["bennyfi"], "bennyfi", "setauthlevel", `{"authorizer":"alice","account":"bob","auth_level":20,"notes":"enroller"}`)
+	broken.ActionData = eos.NewActionDataFromHexData(broken.HexData[:4])
+	trx = &eos.Transaction{
+		TransactionHeader: eos.TransactionHeader{Expiration: eos.JSONTime{Time: time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)}},
+		Actions:           []*eos.Action{broken, trx.Actions[2]},
+	}
+	packed, err = eos.MarshalBinary(trx)
+	assert.NilError(t, err)
+	proposal, err = bennyfi.DecodeProposal("bennyfi", "alice", "prop1", packed, getABI)
+	assert.NilError(t, err)
+	assert.Assert(t, proposal.Actions[0].Data == nil)
+	assert.DeepEqual(t, proposal.Actions[0].HexData, broken.HexData)
+	assert.Assert(t, proposal.Actions[0].DecodeError != "")
+	assert.Equal(t, proposal.Actions[1].DecodeError, "")
+	assert.Assert(t, proposal.IsExpired(time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Assert(t, !proposal.IsExpired(time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC)))
+
+	_, err = bennyfi.DecodeProposal("bennyfi", "alice", "prop1", eos.HexBytes{0x01}, getABI)
+	assert.ErrorContains(t, err, "failed decoding transaction of proposal: prop1")
+}
+
+func TestNewActionArgs(t *testing.T) {
+	assert.DeepEqual(t, bennyfi.NewActionArgs("setauthlevel"), &bennyfi.SetAuthLevelArgs{})
+	assert.DeepEqual(t, bennyfi.NewActionArgs("eraseauth"), &bennyfi.EraseAuthArgs{})
+	assert.DeepEqual(t, bennyfi.NewActionArgs("erasetknrole"), &bennyfi.EraseTokenRoleArgs{})
+	for _, action := range []eos.ActionName{"setsetting", "appndsetting", "clipsetting"} {
+		assert.DeepEqual(t, bennyfi.NewActionArgs(action), &bennyfi.SetterArgs{})
+	}
+	assert.DeepEqual(t, bennyfi.NewActionArgs("erasesetting"), &bennyfi.EraseSettingArgs{})
+	assert.Assert(t, bennyfi.NewActionArgs("unknownact") == nil)
+}
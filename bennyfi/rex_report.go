@@ -0,0 +1,397 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package bennyfi
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/rex"
+)
+
+// RexLifecycle is the order in which the rex state of a rex pool round advances
+var RexLifecycle = []eos.Name{
+	RexStatePreRex,
+	RexStateInSavings,
+	RexStateInLockPeriod,
+	RexStateSold,
+	RexStateWithdrawn,
+}
+
+// RexLifecycleStep is a rex state of the round, At is the zero time when the contract does not record when the
+// state was reached, Due is true when At is the time at which the state is due instead of when it was reached
+type RexLifecycleStep struct {
+	State   eos.Name
+	Reached bool
+	Current bool
+	At      time.Time
+	Due     bool
+}
+
+func (m *RexLifecycleStep) String() string {
+	mark := " "
+	if m.Current {
+		mark = ">"
+	} else if m.Reached {
+		mark = "x"
+	}
+	at := ""
+	if !m.At.IsZero() {
+		if m.Due {
+			at = fmt.Sprintf(" due: %v", m.At.Format(time.RFC3339))
+		} else {
+			at = fmt.Sprintf(" at: %v", m.At.Format(time.RFC3339))
+		}
+	}
+	return fmt.Sprintf("[%v] %v%v", mark, m.State, at)
+}
+
+// RexReportSources holds the optional data used to complete a round rex report, nil sources are skipped.
+// RexBal, Order, Fund and ContractBalance are the rex tables of the bennyfi contract, OpenOrders and Rounds are
+// used to predict the rex sale as described by RexSaleSources, APR is used to project the reward. Unavailable
+// holds the sources that could not be read
+type RexReportSources struct {
+	Pool            *rex.RexPool
+	RexBal          *rex.RexBal
+	Order           *rex.RexOrder
+	OpenOrders      []rex.RexOrder
+	Fund            *rex.RexFund
+	Rounds          []Round
+	ContractBalance *rex.Balance
+	APR             float64
+	Now             time.Time
+	Unavailable     []string
+}
+
+func (m *RexReportSources) saleSources() *RexSaleSources {
+	return &RexSaleSources{
+		RexBal:     m.RexBal,
+		Order:      m.Order,
+		OpenOrders: m.OpenOrders,
+		Fund:       m.Fund,
+		Rounds:     m.Rounds,
+	}
+}
+
+// RoundRexReport follows a rex pool round through the rex lifecycle, comparing the deposits with the value of
+// the rex balance and the realized reward. Notes explain why the round has not advanced to the next state
+type RoundRexReport struct {
+	RoundID         uint64
+	RoundName       string
+	CurrentState    eos.Name
+	RexState        eos.Name
+	Steps           []*RexLifecycleStep
+	TotalDeposits   eos.Asset
+	RexBalance      eos.Asset
+	TotalReward     eos.Asset
+	Valuation       *RoundRexValuation
+	ProjectedReward *eos.Asset
+	RealizedYield   float64
+	Realized        bool
+	PredictedSale   time.Time
+	Notes           []string
+}
+
+func (m *RoundRexReport) addNote(format string, args ...interface{}) {
+	m.Notes = append(m.Notes, fmt.Sprintf(format, args...))
+}
+
+func (m *RoundRexReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "round: %v %q, state: %v, rex state: %v\n", m.RoundID, m.RoundName, m.CurrentState, m.RexState)
+	for _, step := range m.Steps {
+		fmt.Fprintf(&b, "  %v\n", step)
+	}
+	fmt.Fprintf(&b, "  total deposits: %v, rex balance: %v, total reward: %v\n", m.TotalDeposits, m.RexBalance, m.TotalReward)
+	if m.Valuation != nil {
+		fmt.Fprintf(&b, "  current value: %v, gain: %v (%.4f%%)\n", m.Valuation.CurrentValue, m.Valuation.Gain, m.Valuation.ReturnRate*100)
+	}
+	if m.Realized {
+		fmt.Fprintf(&b, "  realized yield: %.4f%%\n", m.RealizedYield*100)
+	}
+	if m.ProjectedReward != nil {
+		fmt.Fprintf(&b, "  projected reward: %v\n", m.ProjectedReward)
+	}
+	if !m.PredictedSale.IsZero() {
+		fmt.Fprintf(&b, "  predicted sale: %v\n", m.PredictedSale.Format(time.RFC3339))
+	}
+	for _, note := range m.Notes {
+		fmt.Fprintf(&b, "  note: %v\n", note)
+	}
+	return b.String()
+}
+
+func rexLifecycleIndex(state eos.Name) int {
+	for i, s := range RexLifecycle {
+		if s == state {
+			return i
+		}
+	}
+	return -1
+}
+
+func parseOptionalAsset(value string, symbol eos.Symbol) (eos.Asset, error) {
+	if value == "" {
+		return eos.Asset{Amount: 0, Symbol: symbol}, nil
+	}
+	return eos.NewAssetFromString(value)
+}
+
+// BuildRoundRexReport builds the rex lifecycle report of a rex pool round
+func BuildRoundRexReport(round *Round, sources *RexReportSources) (*RoundRexReport, error) {
+	if round.RoundType != RoundTypeRexPool {
+		return nil, fmt.Errorf("round: %v is not a rex pool round", round.RoundID)
+	}
+	if sources == nil {
+		sources = &RexReportSources{}
+	}
+	now := sources.Now
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	current := rexLifecycleIndex(round.RexState)
+	if current < 0 {
+		return nil, fmt.Errorf("round: %v has unknown rex state: %v", round.RoundID, round.RexState)
+	}
+	totalDeposits, err := eos.NewAssetFromString(round.TotalDeposits)
+	if err != nil {
+		return nil, fmt.Errorf("round: %v has invalid total deposits: %v, error: %v", round.RoundID, round.TotalDeposits, err)
+	}
+	rexBalance, err := eos.NewAssetFromString(round.RexBalance)
+	if err != nil {
+		return nil, fmt.Errorf("round: %v has invalid rex balance: %v, error: %v", round.RoundID, round.RexBalance, err)
+	}
+	totalReward, err := parseOptionalAsset(round.TotalReward, totalDeposits.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("round: %v has invalid total reward: %v, error: %v", round.RoundID, round.TotalReward, err)
+	}
+	stakedTime, err := round.GetStakedTime()
+	if err != nil {
+		return nil, fmt.Errorf("round: %v, %v", round.RoundID, err)
+	}
+	movedFromSavingsTime, err := round.GetMovedFromSavingsTime()
+	if err != nil {
+		return nil, fmt.Errorf("round: %v, %v", round.RoundID, err)
+	}
+	stakeEndTime, err := round.GetStakeEndTime()
+	if err != nil {
+		return nil, fmt.Errorf("round: %v, %v", round.RoundID, err)
+	}
+
+	report := &RoundRexReport{
+		RoundID:       round.RoundID,
+		RoundName:     round.RoundName,
+		CurrentState:  round.CurrentState,
+		RexState:      round.RexState,
+		TotalDeposits: totalDeposits,
+		RexBalance:    rexBalance,
+		TotalReward:   totalReward,
+	}
+	stepTimes := map[eos.Name]time.Time{
+		RexStateInSavings:    stakedTime,
+		RexStateInLockPeriod: movedFromSavingsTime,
+		RexStateSold:         stakeEndTime,
+	}
+	for i, state := range RexLifecycle {
+		report.Steps = append(report.Steps, &RexLifecycleStep{
+			State:   state,
+			Reached: i <= current,
+			Current: i == current,
+			At:      stepTimes[state],
+			Due:     state == RexStateSold,
+		})
+	}
+
+	if sources.Pool != nil && current < rexLifecycleIndex(RexStateSold) {
+		report.Valuation, err = ValueRoundRex(round, sources.Pool)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if current >= rexLifecycleIndex(RexStateSold) {
+		report.Realized = true
+		if totalDeposits.Amount > 0 {
+			report.RealizedYield = float64(totalReward.Amount) / float64(totalDeposits.Amount)
+		}
+	}
+	if sources.APR > 0 {
+		projected, err := ProjectRoundYield(round, sources.APR)
+		if err != nil {
+			return nil, err
+		}
+		report.ProjectedReward = &projected
+		if report.Realized {
+			report.addNote("realized reward: %v vs projected: %v, difference: %v", totalReward, projected,
+				eos.Asset{Amount: totalReward.Amount - projected.Amount, Symbol: totalReward.Symbol})
+		}
+	}
+	if err := report.diagnose(round, sources, now, stakeEndTime); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (m *RoundRexReport) diagnose(round *Round, sources *RexReportSources, now, stakeEndTime time.Time) error {
+	overdue := !stakeEndTime.IsZero() && now.After(stakeEndTime)
+	switch m.RexState {
+	case RexStatePreRex:
+		if round.CurrentState != RoundAcceptingEntries && round.CurrentState != RoundTimedOut && round.CurrentState != RoundTimedOutUnstaked {
+			m.addNote("round is in state: %v but no rex has been bought", round.CurrentState)
+		}
+	case RexStateInSavings:
+		if overdue {
+			m.addNote("rex still in savings after the stake end time: %v, the mvfrmsavings crank has not run", stakeEndTime.Format(time.RFC3339))
+		}
+	case RexStateInLockPeriod:
+		if sources.RexBal == nil {
+			break
+		}
+		prediction, err := PredictRexSale(round, sources.saleSources(), now)
+		if err != nil {
+			return fmt.Errorf("failed predicting rex sale of round: %v, error: %v", round.RoundID, err)
+		}
+		if len(prediction.RoundsAhead) > 0 {
+			m.addNote("the rex of rounds: %v is sold first", prediction.RoundsAhead)
+		}
+		if !prediction.Matures {
+			m.addNote("the contract does not hold enough maturing rex to sell the round rex balance: %v", m.RexBalance)
+			break
+		}
+		saleTime := prediction.SellTime
+		if saleTime.Before(stakeEndTime) {
+			saleTime = stakeEndTime
+		}
+		m.PredictedSale = saleTime
+		if prediction.QueuedAhead.Amount > 0 {
+			m.addNote("other accounts have: %v in open sell orders, the sale will be queued until the pool has unlent funds", prediction.QueuedAhead)
+		}
+		if overdue && !saleTime.After(now) {
+			m.addNote("rex is matured and the stake end time has passed, the sellrex crank has not run")
+		}
+	case RexStateSold:
+		prediction, err := PredictRexSale(round, sources.saleSources(), now)
+		if err != nil {
+			return fmt.Errorf("failed predicting rex withdrawal of round: %v, error: %v", round.RoundID, err)
+		}
+		if prediction.OrderOpen {
+			m.addNote("rex sold but the sell order is queued waiting for unlent funds, withdrawrex fails until it is filled")
+		} else if prediction.CanWithdraw() || (sources.Fund == nil && sources.Order == nil) {
+			m.addNote("rex sold, the withdrawrex crank has not run")
+		} else {
+			m.addNote("rex sold but the rex fund of the contract is empty")
+		}
+	}
+	for _, unavailable := range sources.Unavailable {
+		m.addNote("%v", unavailable)
+	}
+	if sources.ContractBalance != nil && m.RexState != RexStateWithdrawn {
+		inSellOrders, err := sources.ContractBalance.GetRexInSellOrders()
+		if err == nil && inSellOrders.Amount > 0 {
+			m.addNote("the contract has: %v in queued sell orders waiting for unlent funds", inSellOrders)
+		}
+		fundOut, err := sources.ContractBalance.GetFundOutBalance()
+		if err == nil && fundOut.Amount > 0 {
+			m.addNote("the contract has: %v in rex fund pending withdrawal", fundOut)
+		}
+	}
+	return nil
+}
+
+// GetRexReportSources reads the rex pool and the rex tables of the contract from the rex contract of the chain.
+// The balance table is only defined by the mock rex contract and the rexbal, rexqueue and rexfund tables only
+// by eosio.system, so the rex tables that can not be read are left nil and listed in Unavailable
+func (m *BennyfiContract) GetRexReportSources(apr float64) (*RexReportSources, error) {
+	rexContract, err := m.RexContract()
+	if err != nil {
+		return nil, err
+	}
+	pool, err := rexContract.GetPool()
+	if err != nil {
+		return nil, fmt.Errorf("failed getting rex pool, error: %v", err)
+	}
+	rounds, err := m.GetAllRounds()
+	if err != nil {
+		return nil, fmt.Errorf("failed getting rounds, error: %v", err)
+	}
+	sources := &RexReportSources{
+		Pool:   pool,
+		Rounds: rounds,
+		APR:    apr,
+	}
+	unavailable := func(source string, err error) {
+		sources.Unavailable = append(sources.Unavailable, fmt.Sprintf("%v unavailable, error: %v", source, err))
+	}
+	if sources.RexBal, err = rexContract.GetRexBal(eos.AN(m.ContractName)); err != nil {
+		unavailable("rex bal", err)
+	}
+	if sources.ContractBalance, err = rexContract.GetBalance(eos.Name(m.ContractName)); err != nil {
+		unavailable("rex balance", err)
+	}
+	if sources.Order, err = rexContract.GetRexOrder(eos.AN(m.ContractName)); err != nil {
+		unavailable("rex order", err)
+	}
+	if sources.OpenOrders, err = rexContract.GetOpenRexOrders(); err != nil {
+		unavailable("open rex orders", err)
+	}
+	if sources.Fund, err = rexContract.GetRexFund(eos.AN(m.ContractName)); err != nil {
+		unavailable("rex fund", err)
+	}
+	return sources, nil
+}
+
+func (m *BennyfiContract) RoundRexReport(roundID uint64, apr float64) (*RoundRexReport, error) {
+	round, err := m.GetRound(roundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting round: %v, error: %v", roundID, err)
+	}
+	if round == nil {
+		return nil, fmt.Errorf("round: %v not found", roundID)
+	}
+	sources, err := m.GetRexReportSources(apr)
+	if err != nil {
+		return nil, err
+	}
+	return BuildRoundRexReport(round, sources)
+}
+
+// RexRoundReports builds the rex lifecycle report of every rex pool round that has not been withdrawn
+func (m *BennyfiContract) RexRoundReports(apr float64) ([]*RoundRexReport, error) {
+	sources, err := m.GetRexReportSources(apr)
+	if err != nil {
+		return nil, err
+	}
+	reports := make([]*RoundRexReport, 0)
+	for i := range sources.Rounds {
+		round := &sources.Rounds[i]
+		if round.RoundType != RoundTypeRexPool || round.RexState == RexStateWithdrawn {
+			continue
+		}
+		report, err := BuildRoundRexReport(round, sources)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
@@ -0,0 +1,107 @@
+package bennyfi_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+	"github.com/sebastianmontero/bennyfi-go-client/rex"
+	"gotest.tools/assert"
+)
+
+func rexRound(rexState eos.Name) *bennyfi.Round {
+	return &bennyfi.Round{
+		RoundID:              7,
+		RoundName:            "rex round",
+		RoundType:            bennyfi.RoundTypeRexPool,
+		CurrentState:         bennyfi.RoundOpen,
+		RexState:             rexState,
+		EntryStake:           "10.0000 TLOS",
+		NumParticipants:      10,
+		TotalDeposits:        "100.0000 TLOS",
+		RexBalance:           "1000000.0000 REX",
+		TotalReward:          "0.0000 TLOS",
+		StakingPeriod:        bennyfi.NewMicroseconds(365 * 24 / 2),
+		StakedTime:           "2021-01-01T00:00:00.000",
+		MovedFromSavingsTime: "1970-01-01T00:00:00.000",
+		StakeEndTime:         "2021-07-02T12:00:00.000",
+	}
+}
+
+func TestBuildRoundRexReportLifecycle(t *testing.T) {
+	round := rexRound(bennyfi.RexStateInSavings)
+	report, err := bennyfi.BuildRoundRexReport(round, &bennyfi.RexReportSources{
+		Pool: &rex.RexPool{TotalLendable: "1100.0000 TLOS", TotalRex: "10000000.0000 REX"},
+		APR:  0.1,
+		Now:  time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Steps), 5)
+	assert.Assert(t, report.Steps[0].Reached && !report.Steps[0].Current)
+	assert.Assert(t, report.Steps[1].Current)
+	assert.Equal(t, report.Steps[1].At, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Assert(t, report.Steps[2].At.IsZero())
+	assert.Assert(t, report.Steps[3].Due && !report.Steps[3].Reached)
+	assert.Equal(t, report.Valuation.CurrentValue.String(), "110.0000 TLOS")
+	assert.Equal(t, report.ProjectedReward.String(), "5.0000 TLOS")
+	assert.Assert(t, !report.Realized)
+	assert.Equal(t, len(report.Notes), 0)
+
+	report, err = bennyfi.BuildRoundRexReport(round, &bennyfi.RexReportSources{
+		Now:         time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC),
+		Unavailable: []string{"rex balance unavailable, error: unknown table"},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Notes), 2)
+	assert.Assert(t, strings.Contains(report.Notes[0], "still in savings"), report.Notes[0])
+	assert.Assert(t, strings.Contains(report.Notes[1], "rex balance unavailable"), report.Notes[1])
+
+	round.RoundType = bennyfi.RoundTypeManagerFunded
+	_, err = bennyfi.BuildRoundRexReport(round, nil)
+	assert.ErrorContains(t, err, "not a rex pool round")
+}
+
+func TestBuildRoundRexReportLockPeriod(t *testing.T) {
+	round := rexRound(bennyfi.RexStateInLockPeriod)
+	round.MovedFromSavingsTime = "2021-06-28T12:00:00.000"
+	rexBal := &rex.RexBal{
+		RexBalance: "1000000.0000 REX",
+		RexMaturities: []rex.RexMaturity{
+			{First: "2021-07-03T00:00:00", Second: 10000000000},
+		},
+	}
+	report, err := bennyfi.BuildRoundRexReport(round, &bennyfi.RexReportSources{
+		RexBal:          rexBal,
+		ContractBalance: &rex.Balance{RexInSellOrders: "0.0000 REX", FundOutBalance: "0.0000 TLOS"},
+		Now:             time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, report.PredictedSale, time.Date(2021, 7, 3, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, len(report.Notes), 0)
+
+	report, err = bennyfi.BuildRoundRexReport(round, &bennyfi.RexReportSources{
+		RexBal: rexBal,
+		Now:    time.Date(2021, 7, 4, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, report.PredictedSale, time.Date(2021, 7, 4, 0, 0, 0, 0, time.UTC))
+	assert.Assert(t, strings.Contains(report.Notes[0], "sellrex crank has not run"), report.Notes[0])
+}
+
+func TestBuildRoundRexReportRealized(t *testing.T) {
+	round := rexRound(bennyfi.RexStateSold)
+	round.TotalReward = "4.0000 TLOS"
+	report, err := bennyfi.BuildRoundRexReport(round, &bennyfi.RexReportSources{
+		APR:             0.1,
+		ContractBalance: &rex.Balance{RexInSellOrders: "0.0000 REX", FundOutBalance: "104.0000 TLOS"},
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, report.Realized)
+	assert.Equal(t, report.RealizedYield, 0.04)
+	assert.Equal(t, len(report.Notes), 3)
+	assert.Assert(t, strings.Contains(report.Notes[0], "difference: -1.0000 TLOS"), report.Notes[0])
+	assert.Assert(t, strings.Contains(report.Notes[1], "withdrawrex crank has not run"), report.Notes[1])
+	assert.Assert(t, strings.Contains(report.Notes[2], "104.0000 TLOS in rex fund"), report.Notes[2])
+}
@@ -24,9 +24,12 @@ package bennyfi
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
+	"time"
 
 	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/internal/tablepage"
 )
 
 var (
@@ -67,6 +70,11 @@ func (m *Microseconds) Hrs() int64 {
 	return ms / microsecondsPerHr
 }
 
+func (m *Microseconds) Duration() time.Duration {
+	ms, _ := strconv.ParseInt(m.Microseconds, 10, 64)
+	return time.Duration(ms) * time.Microsecond
+}
+
 func (m *Microseconds) UnmarshalJSON(b []byte) error {
 	ms := make(map[string]interface{})
 	if err := json.Unmarshal(b, &ms); err != nil {
@@ -143,6 +151,32 @@ type Round struct {
 	UpdatedDate            string          `json:"updated_date"`
 }
 
+// GetStakeEndTime returns the zero time if the stake end time has not been set
+func (m *Round) GetStakeEndTime() (time.Time, error) {
+	return parseRoundTime(m.StakeEndTime, "stake end time")
+}
+
+// GetStakedTime returns the zero time if the round has not been staked
+func (m *Round) GetStakedTime() (time.Time, error) {
+	return parseRoundTime(m.StakedTime, "staked time")
+}
+
+// GetMovedFromSavingsTime returns the zero time if the rex of the round has not been moved from savings
+func (m *Round) GetMovedFromSavingsTime() (time.Time, error) {
+	return parseRoundTime(m.MovedFromSavingsTime, "moved from savings time")
+}
+
+func parseRoundTime(value, field string) (time.Time, error) {
+	tp, err := StringToTimePoint(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %v: %v, error: %v", field, value, err)
+	}
+	if tp == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(int64(tp/1000000), int64(tp%1000000)*1000).UTC(), nil
+}
+
 func (m *Round) NumEntriesToClose() uint32 {
 	return m.NumParticipants - m.NumParticipantsEntered
 }
@@ -228,53 +262,53 @@ func (m *BennyfiContract) NewRoundFromRoundArgs(roundArgs *NewRoundArgs) (string
 }
 
 func (m *BennyfiContract) TimedEvents() (string, error) {
-	return m.ExecAction(fmt.Sprintf("%v@open", m.ContractName), "timedevents", nil)
+	return m.ExecAction(m.CrankPermissionLevel(), "timedevents", nil)
 }
 
 func (m *BennyfiContract) TimeoutRounds(callCounter uint64) (string, error) {
 	actionData := make(map[string]interface{})
 	actionData["call_counter"] = callCounter
-	return m.ExecAction(fmt.Sprintf("%v@open", m.ContractName), "timeoutrnds", actionData)
+	return m.ExecAction(m.CrankPermissionLevel(), "timeoutrnds", actionData)
 }
 
 func (m *BennyfiContract) MoveFromSavings(callCounter uint64) (string, error) {
 	actionData := make(map[string]interface{})
 	actionData["call_counter"] = callCounter
-	return m.ExecAction(fmt.Sprintf("%v@open", m.ContractName), "mvfrmsavings", actionData)
+	return m.ExecAction(m.CrankPermissionLevel(), "mvfrmsavings", actionData)
 }
 
 func (m *BennyfiContract) SellRex(callCounter uint64) (string, error) {
 	actionData := make(map[string]interface{})
 	actionData["call_counter"] = callCounter
-	return m.ExecAction(fmt.Sprintf("%v@open", m.ContractName), "sellrex", actionData)
+	return m.ExecAction(m.CrankPermissionLevel(), "sellrex", actionData)
 }
 
 func (m *BennyfiContract) WithdrawRex(callCounter uint64) (string, error) {
 	actionData := make(map[string]interface{})
 	actionData["call_counter"] = callCounter
-	return m.ExecAction(fmt.Sprintf("%v@open", m.ContractName), "withdrawrex", actionData)
+	return m.ExecAction(m.CrankPermissionLevel(), "withdrawrex", actionData)
 }
 
 func (m *BennyfiContract) UnlockRounds(callCounter uint64) (string, error) {
 	actionData := make(map[string]interface{})
 	actionData["call_counter"] = callCounter
-	return m.ExecAction(fmt.Sprintf("%v@open", m.ContractName), "unlockrnds", actionData)
+	return m.ExecAction(m.CrankPermissionLevel(), "unlockrnds", actionData)
 }
 
 func (m *BennyfiContract) UnstakeUnlockedRounds(callCounter uint64) (string, error) {
 	actionData := make(map[string]interface{})
 	actionData["call_counter"] = callCounter
-	return m.ExecAction(fmt.Sprintf("%v@open", m.ContractName), "ustkulckrnds", actionData)
+	return m.ExecAction(m.CrankPermissionLevel(), "ustkulckrnds", actionData)
 }
 
 func (m *BennyfiContract) UnstakeTimedoutRounds(callCounter uint64) (string, error) {
 	actionData := make(map[string]interface{})
 	actionData["call_counter"] = callCounter
-	return m.ExecAction(fmt.Sprintf("%v@open", m.ContractName), "ustktmdrnds", actionData)
+	return m.ExecAction(m.CrankPermissionLevel(), "ustktmdrnds", actionData)
 }
 
 func (m *BennyfiContract) Redraw() (string, error) {
-	return m.ExecAction(fmt.Sprintf("%v@open", m.ContractName), "redraw", nil)
+	return m.ExecAction(m.CrankPermissionLevel(), "redraw", nil)
 }
 
 func (m *BennyfiContract) TstLapseTime(roundId uint64) (string, error) {
@@ -295,6 +329,26 @@ func (m *BennyfiContract) GetRounds() ([]Round, error) {
 	return m.GetRoundsReq(nil)
 }
 
+// GetAllRounds pages through the rounds table, GetRounds only returns the first page
+func (m *BennyfiContract) GetAllRounds() ([]Round, error) {
+	rounds := make([]Round, 0)
+	err := tablepage.ReadAll(func(lowerBound string) (int, bool, error) {
+		var page []Round
+		more, err := tablepage.GetTableRows(m.Contract, eos.GetTableRowsRequest{
+			Table:      "rounds",
+			LowerBound: lowerBound,
+		}, &page)
+		rounds = append(rounds, page...)
+		return len(page), more, err
+	}, func() (string, error) {
+		return tablepage.AfterID(rounds[len(rounds)-1].RoundID), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rounds, nil
+}
+
 func (m *BennyfiContract) GetRoundsbyManager(roundManager eos.AccountName) ([]Round, error) {
 	request := &eos.GetTableRowsRequest{
 		Index:      "2",
@@ -305,6 +359,36 @@ func (m *BennyfiContract) GetRoundsbyManager(roundManager eos.AccountName) ([]Ro
 	return m.GetRoundsReq(request)
 }
 
+// GetAllRoundsbyManager pages through the manager and round id index to read all the rounds of the manager
+func (m *BennyfiContract) GetAllRoundsbyManager(roundManager eos.AccountName) ([]Round, error) {
+	manager, err := eos.StringToName(string(roundManager))
+	if err != nil {
+		return nil, fmt.Errorf("invalid round manager: %v, error: %v", roundManager, err)
+	}
+	rounds := make([]Round, 0)
+	err = tablepage.ReadAll(func(lowerBound string) (int, bool, error) {
+		if lowerBound == "" {
+			lowerBound = tablepage.ComposedBound(manager, 0)
+		}
+		var page []Round
+		more, err := tablepage.GetTableRows(m.Contract, eos.GetTableRowsRequest{
+			Table:      "rounds",
+			Index:      "8",
+			KeyType:    "i128",
+			LowerBound: lowerBound,
+			UpperBound: tablepage.ComposedBound(manager, math.MaxUint64),
+		}, &page)
+		rounds = append(rounds, page...)
+		return len(page), more, err
+	}, func() (string, error) {
+		return tablepage.ComposedBound(manager, rounds[len(rounds)-1].RoundID+1), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rounds, nil
+}
+
 func (m *BennyfiContract) GetRoundsbyTerm(termID uint64) ([]Round, error) {
 	request := &eos.GetTableRowsRequest{}
 	m.FilterRoundsbyTerm(request, termID)
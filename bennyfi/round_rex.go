@@ -0,0 +1,282 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package bennyfi
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/rex"
+)
+
+// RoundRexValuation is the current value of the rex held by a rex pool round compared to its deposits
+type RoundRexValuation struct {
+	RoundID       uint64
+	RexBalance    eos.Asset
+	CurrentValue  eos.Asset
+	TotalDeposits eos.Asset
+	Gain          eos.Asset
+	ReturnRate    float64
+}
+
+func (m *RoundRexValuation) String() string {
+	return fmt.Sprintf("round: %v rex balance: %v, current value: %v, total deposits: %v, gain: %v (%.4f%%)",
+		m.RoundID, m.RexBalance, m.CurrentValue, m.TotalDeposits, m.Gain, m.ReturnRate*100)
+}
+
+// ValueRoundRex values the rex balance of a rex pool round at the current pool rate
+func ValueRoundRex(round *Round, pool *rex.RexPool) (*RoundRexValuation, error) {
+	if round.RoundType != RoundTypeRexPool {
+		return nil, fmt.Errorf("round: %v is not a rex pool round", round.RoundID)
+	}
+	rexBalance, err := eos.NewAssetFromString(round.RexBalance)
+	if err != nil {
+		return nil, fmt.Errorf("round: %v has invalid rex balance: %v, error: %v", round.RoundID, round.RexBalance, err)
+	}
+	totalDeposits, err := eos.NewAssetFromString(round.TotalDeposits)
+	if err != nil {
+		return nil, fmt.Errorf("round: %v has invalid total deposits: %v, error: %v", round.RoundID, round.TotalDeposits, err)
+	}
+	currentValue := eos.Asset{Amount: 0, Symbol: totalDeposits.Symbol}
+	if rexBalance.Amount > 0 {
+		currentValue, err = pool.RexToFund(rexBalance)
+		if err != nil {
+			return nil, fmt.Errorf("failed valuing rex balance of round: %v, error: %v", round.RoundID, err)
+		}
+		if !sameSymbol(currentValue.Symbol, totalDeposits.Symbol) {
+			return nil, fmt.Errorf("round: %v total deposits: %v do not match pool fund symbol: %v", round.RoundID, totalDeposits, currentValue.Symbol)
+		}
+	}
+	valuation := &RoundRexValuation{
+		RoundID:       round.RoundID,
+		RexBalance:    rexBalance,
+		CurrentValue:  currentValue,
+		TotalDeposits: totalDeposits,
+		Gain:          eos.Asset{Amount: currentValue.Amount - totalDeposits.Amount, Symbol: totalDeposits.Symbol},
+	}
+	if totalDeposits.Amount > 0 {
+		valuation.ReturnRate = float64(valuation.Gain.Amount) / float64(totalDeposits.Amount)
+	}
+	return valuation, nil
+}
+
+// ProjectRoundYield projects the yield of the round deposits over the staking period at the specified annual rate,
+// if the round has no deposits yet, the deposits of a full round are used
+func ProjectRoundYield(round *Round, apr float64) (eos.Asset, error) {
+	deposits, err := eos.NewAssetFromString(round.TotalDeposits)
+	if err != nil {
+		return eos.Asset{}, fmt.Errorf("round: %v has invalid total deposits: %v, error: %v", round.RoundID, round.TotalDeposits, err)
+	}
+	if deposits.Amount == 0 {
+		entryStake, err := eos.NewAssetFromString(round.EntryStake)
+		if err != nil {
+			return eos.Asset{}, fmt.Errorf("round: %v has invalid entry stake: %v, error: %v", round.RoundID, round.EntryStake, err)
+		}
+		deposits = eos.Asset{Amount: entryStake.Amount * eos.Int64(round.NumParticipants), Symbol: entryStake.Symbol}
+	}
+	if round.StakingPeriod == nil {
+		return eos.Asset{}, fmt.Errorf("round: %v has no staking period", round.RoundID)
+	}
+	years := float64(round.StakingPeriod.Duration()) / float64(365*24*time.Hour)
+	return eos.Asset{
+		Amount: eos.Int64(math.Floor(float64(deposits.Amount) * apr * years)),
+		Symbol: deposits.Symbol,
+	}, nil
+}
+
+func (m *BennyfiContract) ValueRoundRex(roundID uint64, rexContract rex.RexClient) (*RoundRexValuation, error) {
+	round, err := m.GetRound(roundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting round: %v, error: %v", roundID, err)
+	}
+	if round == nil {
+		return nil, fmt.Errorf("round: %v not found", roundID)
+	}
+	pool, err := rexContract.GetPool()
+	if err != nil {
+		return nil, fmt.Errorf("failed getting rex pool, error: %v", err)
+	}
+	if pool == nil {
+		return nil, fmt.Errorf("rex pool has not been initialized")
+	}
+	return ValueRoundRex(round, pool)
+}
+
+// RexSaleSources holds the rex tables of the bennyfi contract account used to predict rex sales, nil sources
+// are skipped. Order is the sell order of the contract in the rex queue, OpenOrders are the open sell orders of
+// all accounts in fill order and Rounds are the rounds of the contract
+type RexSaleSources struct {
+	RexBal     *rex.RexBal
+	Order      *rex.RexOrder
+	OpenOrders []rex.RexOrder
+	Fund       *rex.RexFund
+	Rounds     []Round
+}
+
+// RexSalePrediction is when the sellrex and withdrawrex cranks can process the rex of a round.
+// SellTime is when the contract holds enough matured rex to sell the rex of the round after the rounds ahead of
+// it, Matures is false if it never will. QueuedAhead is the rex of the open sell orders of other accounts that
+// are filled before a new order, if any the sale will be queued until the pool has enough unlent funds.
+// OrderOpen is true when the contract already has a sell order waiting in the queue, withdrawrex can not succeed
+// until it is filled. Withdrawable is the rex fund balance of the contract, including the proceeds of a filled
+// order
+type RexSalePrediction struct {
+	SellTime     time.Time
+	Matures      bool
+	RoundsAhead  []uint64
+	QueuedAhead  eos.Asset
+	OrderOpen    bool
+	Withdrawable eos.Asset
+}
+
+func (m *RexSaleSources) owner() eos.AccountName {
+	if m.Order != nil {
+		return m.Order.Owner
+	}
+	if m.RexBal != nil {
+		return m.RexBal.Owner
+	}
+	return ""
+}
+
+// CanWithdraw returns true if withdrawrex can withdraw the proceeds from the rex fund
+func (m *RexSalePrediction) CanWithdraw() bool {
+	return !m.OrderOpen && m.Withdrawable.Amount > 0
+}
+
+// PredictRexSale predicts when the sellrex crank can sell the rex of a round in the lock period and when the
+// withdrawrex crank can withdraw the proceeds. The rounds in the lock period are sold first in first out by the
+// time their rex was moved from savings, so the maturing rex of the contract must cover the rex requested by its
+// open sell order and the rex of the rounds ahead before the rex of the round can be sold
+func PredictRexSale(round *Round, sources *RexSaleSources, now time.Time) (*RexSalePrediction, error) {
+	if round.RexState != RexStateInLockPeriod && round.RexState != RexStateSold {
+		return nil, fmt.Errorf("round: %v is not in the rex lock period or sold, rex state: %v", round.RoundID, round.RexState)
+	}
+	rexBalance, err := eos.NewAssetFromString(round.RexBalance)
+	if err != nil {
+		return nil, fmt.Errorf("round: %v has invalid rex balance: %v, error: %v", round.RoundID, round.RexBalance, err)
+	}
+	prediction := &RexSalePrediction{
+		QueuedAhead: eos.Asset{Amount: 0, Symbol: rexBalance.Symbol},
+	}
+	required := rexBalance
+	if sources.Order != nil && sources.Order.IsOpen {
+		prediction.OrderOpen = true
+		requested, err := sources.Order.GetRexRequested()
+		if err != nil {
+			return nil, err
+		}
+		required.Amount += requested.Amount
+	}
+	withdrawable, err := rexFundWithdrawable(sources)
+	if err != nil {
+		return nil, err
+	}
+	prediction.Withdrawable = withdrawable
+	owner := sources.owner()
+	for _, order := range sources.OpenOrders {
+		if order.Owner == owner {
+			break
+		}
+		requested, err := order.GetRexRequested()
+		if err != nil {
+			return nil, err
+		}
+		prediction.QueuedAhead.Amount += requested.Amount
+	}
+	if round.RexState == RexStateSold {
+		prediction.SellTime = now
+		prediction.Matures = true
+		return prediction, nil
+	}
+	if sources.RexBal == nil {
+		return nil, fmt.Errorf("the rex balance of the contract is required to predict the rex sale")
+	}
+	ahead, err := lockPeriodRoundsAhead(round, sources.Rounds)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range ahead {
+		otherRex, err := eos.NewAssetFromString(other.RexBalance)
+		if err != nil {
+			return nil, fmt.Errorf("round: %v has invalid rex balance: %v, error: %v", other.RoundID, other.RexBalance, err)
+		}
+		required.Amount += otherRex.Amount
+		prediction.RoundsAhead = append(prediction.RoundsAhead, other.RoundID)
+	}
+	prediction.SellTime, prediction.Matures, err = sources.RexBal.MaturityTimeFor(required, now)
+	if err != nil {
+		return nil, err
+	}
+	return prediction, nil
+}
+
+// rexFundWithdrawable returns the rex fund balance of the contract plus the proceeds of its filled sell order,
+// which are moved to the rex fund by the next withdraw
+func rexFundWithdrawable(sources *RexSaleSources) (eos.Asset, error) {
+	withdrawable := eos.Asset{}
+	if sources.Fund != nil {
+		balance, err := sources.Fund.GetBalance()
+		if err != nil {
+			return eos.Asset{}, err
+		}
+		withdrawable = balance
+	}
+	if sources.Order != nil && !sources.Order.IsOpen {
+		proceeds, err := sources.Order.GetProceeds()
+		if err != nil {
+			return eos.Asset{}, err
+		}
+		withdrawable.Symbol = proceeds.Symbol
+		withdrawable.Amount += proceeds.Amount
+	}
+	return withdrawable, nil
+}
+
+// lockPeriodRoundsAhead returns the rounds in the lock period whose rex is sold before the rex of the round
+func lockPeriodRoundsAhead(round *Round, rounds []Round) ([]*Round, error) {
+	movedAt, err := round.GetMovedFromSavingsTime()
+	if err != nil {
+		return nil, fmt.Errorf("round: %v, %v", round.RoundID, err)
+	}
+	ahead := make([]*Round, 0)
+	for i := range rounds {
+		other := &rounds[i]
+		if other.RoundID == round.RoundID || other.RoundType != RoundTypeRexPool || other.RexState != RexStateInLockPeriod {
+			continue
+		}
+		otherMovedAt, err := other.GetMovedFromSavingsTime()
+		if err != nil {
+			return nil, fmt.Errorf("round: %v, %v", other.RoundID, err)
+		}
+		if otherMovedAt.Before(movedAt) || (otherMovedAt.Equal(movedAt) && other.RoundID < round.RoundID) {
+			ahead = append(ahead, other)
+		}
+	}
+	sort.Slice(ahead, func(i, j int) bool {
+		return ahead[i].RoundID < ahead[j].RoundID
+	})
+	return ahead, nil
+}
@@ -0,0 +1,118 @@
+package bennyfi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+	"github.com/sebastianmontero/bennyfi-go-client/rex"
+	"gotest.tools/assert"
+)
+
+func TestValueRoundRex(t *testing.T) {
+	pool := &rex.RexPool{TotalLendable: "1100.0000 TLOS", TotalRex: "10000000.0000 REX"}
+	round := &bennyfi.Round{
+		RoundID:       1,
+		RoundType:     bennyfi.RoundTypeRexPool,
+		RexBalance:    "1000000.0000 REX",
+		TotalDeposits: "100.0000 TLOS",
+	}
+	valuation, err := bennyfi.ValueRoundRex(round, pool)
+	assert.NilError(t, err)
+	assert.Equal(t, valuation.CurrentValue.String(), "110.0000 TLOS")
+	assert.Equal(t, valuation.Gain.String(), "10.0000 TLOS")
+	assert.Equal(t, valuation.ReturnRate, 0.1)
+
+	rexAmount, err := pool.FundToRex(valuation.CurrentValue)
+	assert.NilError(t, err)
+	assert.Equal(t, rexAmount.String(), "1000000.0000 REX")
+
+	round.RoundType = bennyfi.RoundTypeManagerFunded
+	_, err = bennyfi.ValueRoundRex(round, pool)
+	assert.ErrorContains(t, err, "not a rex pool round")
+}
+
+func TestProjectRoundYield(t *testing.T) {
+	round := &bennyfi.Round{
+		EntryStake:      "10.0000 TLOS",
+		NumParticipants: 10,
+		TotalDeposits:   "0.0000 TLOS",
+		StakingPeriod:   bennyfi.NewMicroseconds(365 * 24 / 2),
+	}
+	yield, err := bennyfi.ProjectRoundYield(round, 0.1)
+	assert.NilError(t, err)
+	assert.Equal(t, yield.String(), "5.0000 TLOS")
+
+	round.TotalDeposits = "50.0000 TLOS"
+	yield, err = bennyfi.ProjectRoundYield(round, 0.1)
+	assert.NilError(t, err)
+	assert.Equal(t, yield.String(), "2.5000 TLOS")
+
+	round.TotalDeposits = ""
+	_, err = bennyfi.ProjectRoundYield(round, 0.1)
+	assert.ErrorContains(t, err, "invalid total deposits")
+}
+
+func TestPredictRexSale(t *testing.T) {
+	round := rexRound(bennyfi.RexStateInLockPeriod)
+	round.MovedFromSavingsTime = "2021-06-28T12:00:00.000"
+	ahead := rexRound(bennyfi.RexStateInLockPeriod)
+	ahead.RoundID = 3
+	ahead.MovedFromSavingsTime = "2021-06-27T12:00:00.000"
+	behind := rexRound(bennyfi.RexStateInLockPeriod)
+	behind.RoundID = 9
+	behind.MovedFromSavingsTime = "2021-06-29T12:00:00.000"
+	sources := &bennyfi.RexSaleSources{
+		RexBal: &rex.RexBal{
+			Owner:      "bennyfi",
+			RexBalance: "2500000.0000 REX",
+			RexMaturities: []rex.RexMaturity{
+				{First: "2021-07-02T00:00:00", Second: 10000000000},
+				{First: "2021-07-03T00:00:00", Second: 10000000000},
+				{First: "2021-07-04T00:00:00", Second: 5000000000},
+			},
+		},
+		Rounds: []bennyfi.Round{*behind, *round, *ahead},
+	}
+	now := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	prediction, err := bennyfi.PredictRexSale(round, sources, now)
+	assert.NilError(t, err)
+	assert.Assert(t, prediction.Matures)
+	assert.Equal(t, prediction.SellTime, time.Date(2021, 7, 3, 0, 0, 0, 0, time.UTC))
+	assert.DeepEqual(t, prediction.RoundsAhead, []uint64{3})
+
+	sources.Order = &rex.RexOrder{Owner: "bennyfi", RexRequested: "500000.0000 REX", Proceeds: "0.0000 TLOS", OrderTime: "2021-06-30T00:00:00", IsOpen: true}
+	sources.OpenOrders = []rex.RexOrder{
+		{Owner: "alice", RexRequested: "100.0000 REX", OrderTime: "2021-06-29T00:00:00", IsOpen: true},
+		*sources.Order,
+		{Owner: "bob", RexRequested: "200.0000 REX", OrderTime: "2021-06-30T12:00:00", IsOpen: true},
+	}
+	prediction, err = bennyfi.PredictRexSale(round, sources, now)
+	assert.NilError(t, err)
+	assert.Equal(t, prediction.SellTime, time.Date(2021, 7, 4, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, prediction.QueuedAhead.String(), "100.0000 REX")
+	assert.Assert(t, prediction.OrderOpen)
+
+	sources.Rounds = append(sources.Rounds, *ahead)
+	sources.Rounds[3].RoundID = 2
+	prediction, err = bennyfi.PredictRexSale(round, sources, now)
+	assert.NilError(t, err)
+	assert.Assert(t, !prediction.Matures)
+
+	sold := rexRound(bennyfi.RexStateSold)
+	prediction, err = bennyfi.PredictRexSale(sold, sources, now)
+	assert.NilError(t, err)
+	assert.Assert(t, !prediction.CanWithdraw())
+
+	sources.Order.IsOpen = false
+	sources.Order.Proceeds = "110.0000 TLOS"
+	sources.Fund = &rex.RexFund{Owner: "bennyfi", Balance: "5.0000 TLOS"}
+	prediction, err = bennyfi.PredictRexSale(sold, sources, now)
+	assert.NilError(t, err)
+	assert.Assert(t, prediction.CanWithdraw())
+	assert.Equal(t, prediction.Withdrawable.String(), "115.0000 TLOS")
+
+	_, err = bennyfi.PredictRexSale(rexRound(bennyfi.RexStateInSavings), sources, now)
+	assert.ErrorContains(t, err, "is not in the rex lock period or sold")
+}
@@ -22,12 +22,16 @@
 package bennyfi
 
 import (
+	"encoding/hex"
 	"fmt"
 	"log"
+	"reflect"
 	"strconv"
 	"time"
 
 	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/internal/abiutil"
+	"github.com/sebastianmontero/bennyfi-go-client/internal/tablepage"
 )
 
 var (
@@ -44,14 +48,31 @@ type Setting struct {
 
 func (m *BennyfiContract) setter(owner eos.AccountName,
 	key string, flexValue *FlexValue, action eos.ActionName) (string, error) {
-	actionData := m.getSetterData(owner, key, flexValue)
-	return m.ExecAction(string(owner), string(action), actionData)
+	contractAction, err := m.SetterAction(owner, key, flexValue, action)
+	if err != nil {
+		return "", err
+	}
+	return m.Exec(contractAction)
 }
 
 func (m *BennyfiContract) proposeSetter(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, owner eos.AccountName,
 	key string, flexValue *FlexValue, action eos.ActionName) (string, error) {
-	actionData := m.getSetterData(owner, key, flexValue)
-	return m.ProposeAction(proposerName, requested, expireIn, string(owner), string(action), actionData)
+	contractAction, err := m.SetterAction(owner, key, flexValue, action)
+	if err != nil {
+		return "", err
+	}
+	return m.Propose(proposerName, requested, expireIn, contractAction)
+}
+
+// SetterAction validates the value against the settings registry and builds the setsetting, appndsetting or
+// clipsetting action
+func (m *BennyfiContract) SetterAction(owner eos.AccountName,
+	key string, flexValue *FlexValue, action eos.ActionName) (*ContractAction, error) {
+	err := m.Settings.validateAction(action, key, flexValue)
+	if err != nil {
+		return nil, err
+	}
+	return NewContractAction(string(owner), action, m.getSetterData(owner, key, flexValue)), nil
 }
 
 func (m *BennyfiContract) getSetterData(owner eos.AccountName,
@@ -100,29 +121,47 @@ func (m *BennyfiContract) ProposeClipSetting(proposerName interface{}, requested
 }
 
 func (m *BennyfiContract) EraseSetting(owner eos.AccountName, key string) (string, error) {
-
-	actionData := make(map[string]interface{})
-	actionData["setter"] = owner
-	actionData["key"] = key
-
-	return m.ExecAction(owner, "erasesetting", actionData)
+	return m.Exec(m.EraseSettingAction(owner, key))
 }
 
 func (m *BennyfiContract) ProposeEraseSetting(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, owner eos.AccountName,
 	key string) (string, error) {
+	return m.Propose(proposerName, requested, expireIn, m.EraseSettingAction(owner, key))
+}
 
+func (m *BennyfiContract) EraseSettingAction(owner eos.AccountName, key string) *ContractAction {
 	actionData := make(map[string]interface{})
 	actionData["setter"] = owner
 	actionData["key"] = key
-	return m.ProposeAction(proposerName, requested, expireIn, string(owner), "erasesetting", actionData)
+	return NewContractAction(owner, "erasesetting", actionData)
 }
 
 func (m *BennyfiContract) GetSettings() ([]Setting, error) {
 	return m.GetSettingsReq(nil)
 }
 
+// GetAllSettings returns every row of the settings table reading it page by page
+func (m *BennyfiContract) GetAllSettings() ([]Setting, error) {
+	settings := make([]Setting, 0)
+	err := tablepage.ReadAll(func(lowerBound string) (int, bool, error) {
+		var page []Setting
+		more, err := tablepage.GetTableRows(m.Contract, eos.GetTableRowsRequest{
+			Table:      "settings",
+			LowerBound: lowerBound,
+		}, &page)
+		settings = append(settings, page...)
+		return len(page), more, err
+	}, func() (string, error) {
+		return tablepage.AfterID(settings[len(settings)-1].ID), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
 func (m *BennyfiContract) GetSetting(key string) (*Setting, error) {
-	settings, err := m.GetSettings()
+	settings, err := m.GetAllSettings()
 	if err != nil {
 		return nil, err
 	}
@@ -149,8 +188,12 @@ func (m *BennyfiContract) GetSettingsReq(req *eos.GetTableRowsRequest) ([]Settin
 }
 
 func (m *BennyfiContract) SetupConfigSettings(owner eos.AccountName, settings interface{}) error {
-	for _, value := range settings.([]interface{}) {
-		err := m.SetupConfigSetting(owner, value.(map[interface{}]interface{}))
+	configSettings, err := getConfigSettings(settings)
+	if err != nil {
+		return err
+	}
+	for _, configSetting := range configSettings {
+		err := m.SetupConfigSetting(owner, configSetting)
 		if err != nil {
 			return err
 		}
@@ -173,8 +216,12 @@ func (m *BennyfiContract) SetupConfigSetting(owner eos.AccountName, configSettin
 }
 
 func (m *BennyfiContract) ProposeConfigSettings(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, owner eos.AccountName, settings interface{}) error {
-	for _, value := range settings.([]interface{}) {
-		err := m.ProposeConfigSetting(proposerName, requested, expireIn, owner, value.(map[interface{}]interface{}))
+	configSettings, err := getConfigSettings(settings)
+	if err != nil {
+		return err
+	}
+	for _, configSetting := range configSettings {
+		err := m.ProposeConfigSetting(proposerName, requested, expireIn, owner, configSetting)
 		if err != nil {
 			return err
 		}
@@ -196,62 +243,145 @@ func (m *BennyfiContract) ProposeConfigSetting(proposerName interface{}, request
 	return nil
 }
 
+func getConfigSettings(settings interface{}) ([]map[interface{}]interface{}, error) {
+	values, ok := settings.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("config settings must be a list, found: %T", settings)
+	}
+	configSettings := make([]map[interface{}]interface{}, 0, len(values))
+	for i, value := range values {
+		configSetting, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("config setting at position: %v must be a map, found: %T", i, value)
+		}
+		configSettings = append(configSettings, configSetting)
+	}
+	return configSettings, nil
+}
+
 func GetConfigSetting(setting map[interface{}]interface{}) (*Setting, error) {
 
-	fv, err := StringToSetting(setting["type"].(string), setting["value"].(string))
+	key, err := getConfigSettingString(setting, "key")
+	if err != nil {
+		return nil, err
+	}
+	settingType, err := getConfigSettingString(setting, "type")
+	if err != nil {
+		return nil, err
+	}
+	value, err := getConfigSettingString(setting, "value")
+	if err != nil {
+		return nil, err
+	}
+	fv, err := StringToSetting(settingType, value)
 	if err != nil {
 		return nil, fmt.Errorf("failed parsing string setting to flex value, error: %v", err)
 	}
 	return &Setting{
-		Key: setting["key"].(string),
+		Key: key,
 		Values: []FlexValue{
 			fv,
 		},
 	}, nil
 }
 
+func getConfigSettingString(setting map[interface{}]interface{}, property string) (string, error) {
+	value, ok := setting[property]
+	if !ok {
+		return "", fmt.Errorf("config setting: %v is missing the %v property", setting, property)
+	}
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int, int64, uint64, float64, bool:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("config setting: %v property: %v must be a string, found: %T", setting, property, value)
+	}
+}
+
+// StringToSetting parses the string representation of a FlexValue of the specified variant type, the output of
+// FlexValue.String() can always be parsed back to an identical value. Time points can be specified in RFC3339 format
+// or as seconds since epoch, checksums in hex.
 func StringToSetting(settingType, stringValue string) (FlexValue, error) {
-	if settingType == "string" {
-		return FlexValue{
-			BaseVariant: eos.BaseVariant{
-				TypeID: GetVariants().TypeID("string"),
-				Impl:   stringValue,
-			},
-		}, nil
-	} else if settingType == "name" {
-		return FlexValue{
-			BaseVariant: eos.BaseVariant{
-				TypeID: GetVariants().TypeID("name"),
-				Impl:   eos.Name(stringValue),
-			},
-		}, nil
-	} else if settingType == "int64" {
+	var impl interface{}
+	switch settingType {
+	case "monostate":
+		if stringValue != "" {
+			return FlexValue{}, fmt.Errorf("monostate settings value must be empty, found: %v", stringValue)
+		}
+		impl = int64(0)
+	case "string":
+		impl = stringValue
+	case "name":
+		impl = eos.Name(stringValue)
+	case "int64":
 		i, err := strconv.ParseInt(stringValue, 10, 64)
 		if err != nil {
 			return FlexValue{}, fmt.Errorf("cannot convert settings value to int64: %v", err)
 		}
-		return FlexValue{
-			BaseVariant: eos.BaseVariant{
-				TypeID: GetVariants().TypeID("int64"),
-				Impl:   int64(i),
-			},
-		}, nil
-	} else if settingType == "asset" {
+		impl = i
+	case "asset":
 		a, err := eos.NewAssetFromString(stringValue)
 		if err != nil {
 			return FlexValue{}, fmt.Errorf("cannot convert settings value to asset: %v", err)
 		}
-		return FlexValue{
-			BaseVariant: eos.BaseVariant{
-				TypeID: GetVariants().TypeID("asset"),
-				Impl:   a,
-			},
-		}, nil
-	} else if settingType == "timepoint" {
-		fmt.Println("type: timepoint is not yet supported from CLI")
-		return FlexValue{}, fmt.Errorf("not yet supported")
+		impl = a
+	case "time_point", "timepoint":
+		tp, err := StringToTimePoint(stringValue)
+		if err != nil {
+			return FlexValue{}, fmt.Errorf("cannot convert settings value to time point: %v", err)
+		}
+		settingType = "time_point"
+		impl = tp
+	case "checksum256":
+		checksum, err := hex.DecodeString(stringValue)
+		if err != nil {
+			return FlexValue{}, fmt.Errorf("cannot convert settings value to checksum256: %v", err)
+		}
+		if len(checksum) != 32 {
+			return FlexValue{}, fmt.Errorf("cannot convert settings value to checksum256, expected 32 bytes found: %v", len(checksum))
+		}
+		impl = eos.Checksum256(checksum)
+	default:
+		return FlexValue{}, fmt.Errorf("unsupported settings data type: %v", settingType)
+	}
+	return FlexValue{
+		BaseVariant: eos.BaseVariant{
+			TypeID: GetVariants().TypeID(settingType),
+			Impl:   impl,
+		},
+	}, nil
+}
+
+var timePointFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05",
+}
+
+// StringToTimePoint parses RFC3339 time, RFC3339 time without time zone (UTC is assumed) or seconds since epoch
+func StringToTimePoint(stringValue string) (eos.TimePoint, error) {
+	if seconds, err := strconv.ParseInt(stringValue, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, fmt.Errorf("time point must not be before epoch: %v", stringValue)
+		}
+		return eos.TimePoint(seconds * 1000000), nil
+	}
+	for _, format := range timePointFormats {
+		t, err := time.Parse(format, stringValue)
+		if err == nil {
+			if t.Before(time.Unix(0, 0)) {
+				return 0, fmt.Errorf("time point must not be before epoch: %v", stringValue)
+			}
+			return eos.TimePoint(t.Unix()*1000000 + int64(t.Nanosecond()/1000)), nil
+		}
 	}
-	return FlexValue{}, fmt.Errorf("unsupported settings data type: %v", settingType)
+	return 0, fmt.Errorf("invalid time point: %v, expected RFC3339 time or seconds since epoch", stringValue)
+}
+
+func timePointToString(tp eos.TimePoint) string {
+	return time.Unix(int64(tp/1000000), int64(tp%1000000)*1000).UTC().Format("2006-01-02T15:04:05.000000Z")
 }
 
 // InvalidTypeError is used the type of a FlexValue doesn't match expectations
@@ -262,11 +392,10 @@ type InvalidTypeError struct {
 }
 
 func (c *InvalidTypeError) Error() string {
-	return fmt.Sprintf("received an unexpected type %T for metadata variant %T", c.ExpectedType, c.FlexValue)
+	return fmt.Sprintf("%v, expected type: %v", c.Label, c.ExpectedType)
 }
 
-// FlexValueVariant may hold a name, int64, asset, string, or time_point
-var FlexValueVariant = eos.NewVariantDefinition([]eos.VariantType{
+var flexValueTypes = []eos.VariantType{
 	{Name: "monostate", Type: int64(0)},
 	{Name: "name", Type: eos.Name("")},
 	{Name: "string", Type: ""},
@@ -274,7 +403,10 @@ var FlexValueVariant = eos.NewVariantDefinition([]eos.VariantType{
 	{Name: "time_point", Type: eos.TimePoint(0)},
 	{Name: "int64", Type: int64(0)},
 	{Name: "checksum256", Type: eos.Checksum256([]byte("0"))},
-})
+}
+
+// FlexValueVariant may hold a name, int64, asset, string, or time_point
+var FlexValueVariant = eos.NewVariantDefinition(flexValueTypes)
 
 // GetVariants returns the definition of types compatible with FlexValue
 func GetVariants() *eos.VariantDefinition {
@@ -287,7 +419,24 @@ type FlexValue struct {
 	eos.BaseVariant
 }
 
+// TypeName returns the name of the variant type, i.e. name, int64, asset
+func (fv *FlexValue) TypeName() string {
+	_, typeName, _ := fv.Obtain(FlexValueVariant)
+	return typeName
+}
+
+func (fv *FlexValue) invalidTypeError(expectedType string) *InvalidTypeError {
+	return &InvalidTypeError{
+		Label:        fmt.Sprintf("received an unexpected type %v for variant %T", fv.TypeName(), fv),
+		ExpectedType: expectedType,
+		FlexValue:    fv,
+	}
+}
+
 func (fv *FlexValue) String() string {
+	if fv.TypeName() == "monostate" {
+		return ""
+	}
 	switch v := fv.Impl.(type) {
 	case eos.Name:
 		return string(v)
@@ -298,7 +447,7 @@ func (fv *FlexValue) String() string {
 	case string:
 		return v
 	case eos.TimePoint:
-		return v.String()
+		return timePointToString(v)
 	case eos.Checksum256:
 		return v.String()
 	default:
@@ -312,11 +461,7 @@ func (fv *FlexValue) TimePoint() (eos.TimePoint, error) {
 	case eos.TimePoint:
 		return v, nil
 	default:
-		return 0, &InvalidTypeError{
-			Label:        fmt.Sprintf("received an unexpected type %T for variant %T", v, fv),
-			ExpectedType: "eos.TimePoint",
-			FlexValue:    fv,
-		}
+		return 0, fv.invalidTypeError("eos.TimePoint")
 	}
 }
 
@@ -326,11 +471,7 @@ func (fv *FlexValue) Asset() (eos.Asset, error) {
 	case eos.Asset:
 		return v, nil
 	default:
-		return eos.Asset{}, &InvalidTypeError{
-			Label:        fmt.Sprintf("received an unexpected type %T for variant %T", v, fv),
-			ExpectedType: "eos.Asset",
-			FlexValue:    fv,
-		}
+		return eos.Asset{}, fv.invalidTypeError("eos.Asset")
 	}
 }
 
@@ -342,26 +483,18 @@ func (fv *FlexValue) Name() (eos.Name, error) {
 	case string:
 		return eos.Name(v), nil
 	default:
-		return eos.Name(""), &InvalidTypeError{
-			Label:        fmt.Sprintf("received an unexpected type %T for variant %T", v, fv),
-			ExpectedType: "eos.Name",
-			FlexValue:    fv,
-		}
+		return eos.Name(""), fv.invalidTypeError("eos.Name")
 	}
 }
 
-// Int64 returns a string value of found content or it panics
+// Int64 returns a string value of found content or it panics, monostate values also hold an int64
+// so the variant type is checked
 func (fv *FlexValue) Int64() (int64, error) {
-	switch v := fv.Impl.(type) {
-	case int64:
-		return v, nil
-	default:
-		return -1000000, &InvalidTypeError{
-			Label:        fmt.Sprintf("received an unexpected type %T for variant %T", v, fv),
-			ExpectedType: "int64",
-			FlexValue:    fv,
-		}
+	v, ok := fv.Impl.(int64)
+	if !ok || fv.TypeName() != "int64" {
+		return -1000000, fv.invalidTypeError("int64")
 	}
+	return v, nil
 }
 
 // IsEqual evaluates if the two FlexValues have the same types and values (deep compare)
@@ -390,7 +523,97 @@ func (fv *FlexValue) UnmarshalJSON(data []byte) error {
 	return fv.BaseVariant.UnmarshalJSON(data, FlexValueVariant)
 }
 
-// UnmarshalBinary ...
+// MarshalBinary encodes the value as an ABI variant, the type id followed by the value, monostate has no value.
+// Setting.Values holds FlexValues, not pointers, hence the value receiver
+func (fv FlexValue) MarshalBinary(encoder *eos.Encoder) error {
+	if int(fv.TypeID) >= len(flexValueTypes) {
+		return fmt.Errorf("unknown flex value type id: %v", fv.TypeID)
+	}
+	typeName := flexValueTypes[fv.TypeID].Name
+	if reflect.TypeOf(fv.Impl) != reflect.TypeOf(flexValueTypes[fv.TypeID].Type) {
+		return fmt.Errorf("flex value of type: %v holds a value of type: %T", typeName, fv.Impl)
+	}
+	if checksum, ok := fv.Impl.(eos.Checksum256); ok && len(checksum) != 32 {
+		return fmt.Errorf("checksum256 flex value must be 32 bytes long, found: %v", len(checksum))
+	}
+	if asset, ok := fv.Impl.(eos.Asset); ok {
+		if err := validateAsset(asset); err != nil {
+			return fmt.Errorf("invalid asset flex value, error: %v", err)
+		}
+	}
+	err := encoder.Encode(eos.Varuint32(fv.TypeID))
+	if err != nil {
+		return fmt.Errorf("failed encoding flex value type id, error: %v", err)
+	}
+	if typeName == "monostate" {
+		return nil
+	}
+	err = encoder.Encode(fv.Impl)
+	if err != nil {
+		return fmt.Errorf("failed encoding flex value of type: %v, error: %v", typeName, err)
+	}
+	return nil
+}
+
+// UnmarshalBinary decodes an ABI variant
 func (fv *FlexValue) UnmarshalBinary(decoder *eos.Decoder) error {
-	return fv.BaseVariant.UnmarshalBinaryVariant(decoder, FlexValueVariant)
+	typeID, err := decoder.ReadUvarint32()
+	if err != nil {
+		return fmt.Errorf("unable to read flex value type id: %v", err)
+	}
+	if int(typeID) >= len(flexValueTypes) {
+		return fmt.Errorf("unknown flex value type id: %v", typeID)
+	}
+	typeName := flexValueTypes[typeID].Name
+	var impl interface{}
+	switch typeName {
+	case "monostate":
+		impl = int64(0)
+	case "name":
+		impl, err = decoder.ReadName()
+	case "string":
+		impl, err = abiutil.ReadString(decoder)
+	case "asset":
+		var asset eos.Asset
+		asset, err = decoder.ReadAsset()
+		if err == nil {
+			err = validateAsset(asset)
+		}
+		impl = asset
+	case "time_point":
+		impl, err = decoder.ReadTimePoint()
+	case "int64":
+		impl, err = decoder.ReadInt64()
+	case "checksum256":
+		impl, err = decoder.ReadChecksum256()
+	}
+	if err != nil {
+		return fmt.Errorf("unable to decode flex value of type: %v, error: %v", typeName, err)
+	}
+	fv.BaseVariant = eos.BaseVariant{
+		TypeID: typeID,
+		Impl:   impl,
+	}
+	return nil
+}
+
+// validateAsset applies the checks of the asset type of the contracts, the symbol must have at most 18 decimals
+// and a code of 1 to 7 upper case letters and the amount must be within +-(2^62 - 1)
+func validateAsset(asset eos.Asset) error {
+	if asset.Precision > 18 {
+		return fmt.Errorf("asset precision must be at most 18, found: %v", asset.Precision)
+	}
+	if len(asset.Symbol.Symbol) == 0 || len(asset.Symbol.Symbol) > 7 {
+		return fmt.Errorf("asset symbol code must be 1 to 7 characters long, found: %q", asset.Symbol.Symbol)
+	}
+	for _, c := range asset.Symbol.Symbol {
+		if c < 'A' || c > 'Z' {
+			return fmt.Errorf("asset symbol code must only contain upper case letters, found: %q", asset.Symbol.Symbol)
+		}
+	}
+	maxAmount := int64(1)<<62 - 1
+	if asset.Amount > eos.Int64(maxAmount) || asset.Amount < eos.Int64(-maxAmount) {
+		return fmt.Errorf("asset amount out of range: %v", int64(asset.Amount))
+	}
+	return nil
 }
@@ -0,0 +1,289 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package bennyfi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"gopkg.in/yaml.v2"
+)
+
+type TypedValue struct {
+	Type  string `yaml:"type" json:"type"`
+	Value string `yaml:"value" json:"value"`
+}
+
+type SettingEntry struct {
+	Key    string        `yaml:"key" json:"key"`
+	Values []*TypedValue `yaml:"values" json:"values"`
+}
+
+// SettingsDocument is the typed file representation of the settings table, it is written and read
+// as JSON if the file has a .json extension and as YAML otherwise
+type SettingsDocument struct {
+	Settings []*SettingEntry `yaml:"settings" json:"settings"`
+}
+
+func NewSettingsDocument(settings []Setting) *SettingsDocument {
+	entries := make([]*SettingEntry, 0, len(settings))
+	for _, setting := range settings {
+		values := make([]*TypedValue, 0, len(setting.Values))
+		for i := range setting.Values {
+			values = append(values, &TypedValue{
+				Type:  setting.Values[i].TypeName(),
+				Value: setting.Values[i].String(),
+			})
+		}
+		entries = append(entries, &SettingEntry{
+			Key:    setting.Key,
+			Values: values,
+		})
+	}
+	return &SettingsDocument{
+		Settings: entries,
+	}
+}
+
+func LoadSettingsDocument(file string) (*SettingsDocument, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading settings file: %v, error: %v", file, err)
+	}
+	document := &SettingsDocument{}
+	if isJSONFile(file) {
+		decoder := json.NewDecoder(strings.NewReader(string(data)))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(document)
+	} else {
+		err = yaml.UnmarshalStrict(data, document)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing settings file: %v, error: %v", file, err)
+	}
+	return document, nil
+}
+
+func (m *SettingsDocument) WriteFile(file string) error {
+	var data []byte
+	var err error
+	if isJSONFile(file) {
+		data, err = json.MarshalIndent(m, "", "  ")
+	} else {
+		data, err = yaml.Marshal(m)
+	}
+	if err != nil {
+		return fmt.Errorf("failed serializing settings document, error: %v", err)
+	}
+	err = ioutil.WriteFile(file, data, 0644)
+	if err != nil {
+		return fmt.Errorf("failed writing settings file: %v, error: %v", file, err)
+	}
+	return nil
+}
+
+func isJSONFile(file string) bool {
+	return strings.ToLower(filepath.Ext(file)) == ".json"
+}
+
+// ToSettings parses the typed values and validates the resulting settings against the registry
+func (m *SettingsDocument) ToSettings(registry SettingsRegistry) ([]Setting, error) {
+	settings := make([]Setting, 0, len(m.Settings))
+	keys := make(map[string]bool)
+	for i, entry := range m.Settings {
+		if entry.Key == "" {
+			return nil, fmt.Errorf("setting at position: %v has no key", i)
+		}
+		if keys[entry.Key] {
+			return nil, fmt.Errorf("setting: %v is defined more than once", entry.Key)
+		}
+		keys[entry.Key] = true
+		if len(entry.Values) == 0 {
+			return nil, fmt.Errorf("setting: %v has no values", entry.Key)
+		}
+		setting := Setting{
+			Key:    entry.Key,
+			Values: make([]FlexValue, 0, len(entry.Values)),
+		}
+		for j, value := range entry.Values {
+			fv, err := StringToSetting(value.Type, value.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value at position: %v for setting: %v, error: %v", j, entry.Key, err)
+			}
+			setting.Values = append(setting.Values, fv)
+		}
+		err := registry.ValidateSetting(&setting)
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
+
+func (m *BennyfiContract) ExportSettings() (*SettingsDocument, error) {
+	settings, err := m.GetAllSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed getting settings to export, error: %v", err)
+	}
+	return NewSettingsDocument(settings), nil
+}
+
+func (m *BennyfiContract) ExportSettingsToFile(file string) error {
+	document, err := m.ExportSettings()
+	if err != nil {
+		return err
+	}
+	return document.WriteFile(file)
+}
+
+// SettingOp is a single setter action required to converge the settings table to the desired settings
+type SettingOp struct {
+	Action eos.ActionName
+	Key    string
+	Value  *FlexValue
+}
+
+func (m *SettingOp) String() string {
+	if m.Value == nil {
+		return fmt.Sprintf("%v key: %v", m.Action, m.Key)
+	}
+	return fmt.Sprintf("%v key: %v, value: %v(%v)", m.Action, m.Key, m.Value.TypeName(), m.Value)
+}
+
+func (m *SettingOp) ContractAction(contract *BennyfiContract, owner eos.AccountName) (*ContractAction, error) {
+	if m.Action == "erasesetting" {
+		return contract.EraseSettingAction(owner, m.Key), nil
+	}
+	return contract.SetterAction(owner, m.Key, m.Value, m.Action)
+}
+
+type SettingsPlan []*SettingOp
+
+func (m SettingsPlan) String() string {
+	if len(m) == 0 {
+		return "settings are in sync, no changes required"
+	}
+	ops := make([]string, 0, len(m))
+	for i, op := range m {
+		ops = append(ops, fmt.Sprintf("%v. %v", i+1, op))
+	}
+	return strings.Join(ops, "\n")
+}
+
+// PlanSettings computes the setter actions required for the current settings to match the desired ones,
+// if prune is true current settings not in desired are erased. The order of the values of list settings is
+// not preserved, see PlanSettingValues.
+func PlanSettings(desired, current []Setting, prune bool) SettingsPlan {
+	plan := make(SettingsPlan, 0)
+	currentSettings := make(map[string]*Setting, len(current))
+	for i := range current {
+		currentSettings[current[i].Key] = &current[i]
+	}
+	desiredKeys := make(map[string]bool, len(desired))
+	for i := range desired {
+		desiredKeys[desired[i].Key] = true
+		var currentValues []FlexValue
+		if currentSetting, ok := currentSettings[desired[i].Key]; ok {
+			currentValues = currentSetting.Values
+		}
+		plan = append(plan, PlanSettingValues(desired[i].Key, currentValues, desired[i].Values)...)
+	}
+	if prune {
+		for _, setting := range current {
+			if !desiredKeys[setting.Key] {
+				plan = append(plan, &SettingOp{
+					Action: "erasesetting",
+					Key:    setting.Key,
+				})
+			}
+		}
+	}
+	return plan
+}
+
+// PlanSettingValues computes the setter actions required for the values of a setting to change from
+// current to desired, list settings are updated with the minimal sequence of appends and clips. Values are
+// compared as multisets as in PlanSettingList, so a list that only differs in order requires no changes.
+func PlanSettingValues(key string, current, desired []FlexValue) SettingsPlan {
+	if sameFlexValueMultiset(current, desired) {
+		return make(SettingsPlan, 0)
+	}
+	if len(current) > 1 || len(desired) > 1 {
+		return PlanSettingList(key, current, desired)
+	}
+	if len(desired) == 0 {
+		return SettingsPlan{&SettingOp{
+			Action: "erasesetting",
+			Key:    key,
+		}}
+	}
+	return SettingsPlan{&SettingOp{
+		Action: "setsetting",
+		Key:    key,
+		Value:  &desired[0],
+	}}
+}
+
+func (m *BennyfiContract) PlanSettingsImport(document *SettingsDocument, prune bool) (SettingsPlan, error) {
+	desired, err := document.ToSettings(m.Settings)
+	if err != nil {
+		return nil, err
+	}
+	current, err := m.GetAllSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed getting current settings, error: %v", err)
+	}
+	return PlanSettings(desired, current, prune), nil
+}
+
+func (m *BennyfiContract) ApplySettingsPlan(owner eos.AccountName, plan SettingsPlan) error {
+	for _, op := range plan {
+		action, err := op.ContractAction(m, owner)
+		if err != nil {
+			return fmt.Errorf("failed applying settings op: %v, error: %v", op, err)
+		}
+		_, err = m.Exec(action)
+		if err != nil {
+			return fmt.Errorf("failed applying settings op: %v, error: %v", op, err)
+		}
+	}
+	return nil
+}
+
+// ProposeSettingsPlan creates a single multisig proposal containing all the actions of the plan
+func (m *BennyfiContract) ProposeSettingsPlan(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, owner eos.AccountName, plan SettingsPlan) (string, error) {
+	actions := make([]*ContractAction, 0, len(plan))
+	for _, op := range plan {
+		action, err := op.ContractAction(m, owner)
+		if err != nil {
+			return "", fmt.Errorf("failed proposing settings op: %v, error: %v", op, err)
+		}
+		actions = append(actions, action)
+	}
+	return m.Propose(proposerName, requested, expireIn, actions...)
+}
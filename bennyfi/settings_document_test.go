@@ -0,0 +1,57 @@
+package bennyfi_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+	"gotest.tools/assert"
+)
+
+func flexValue(t *testing.T, settingType, value string) bennyfi.FlexValue {
+	fv, err := bennyfi.StringToSetting(settingType, value)
+	assert.NilError(t, err)
+	return fv
+}
+
+func TestSettingsDocumentRoundTrip(t *testing.T) {
+	settings := []bennyfi.Setting{
+		{Key: "VRF_CONTRACT", Values: []bennyfi.FlexValue{flexValue(t, "name", "orng.wax")}},
+		{Key: "FEES", Values: []bennyfi.FlexValue{flexValue(t, "int64", "10"), flexValue(t, "int64", "20")}},
+	}
+	document := bennyfi.NewSettingsDocument(settings)
+	for _, file := range []string{"settings.yaml", "settings.json"} {
+		path := filepath.Join(t.TempDir(), file)
+		assert.NilError(t, document.WriteFile(path))
+		loaded, err := bennyfi.LoadSettingsDocument(path)
+		assert.NilError(t, err)
+		parsed, err := loaded.ToSettings(bennyfi.DefaultSettingsRegistry())
+		assert.NilError(t, err)
+		assert.Equal(t, len(bennyfi.PlanSettings(parsed, settings, true)), 0)
+	}
+
+	document.Settings[0].Values[0].Type = "int64"
+	_, err := document.ToSettings(bennyfi.DefaultSettingsRegistry())
+	assert.ErrorContains(t, err, "VRF_CONTRACT")
+}
+
+func TestPlanSettings(t *testing.T) {
+	current := []bennyfi.Setting{
+		{Key: "A", Values: []bennyfi.FlexValue{flexValue(t, "int64", "1")}},
+		{Key: "B", Values: []bennyfi.FlexValue{flexValue(t, "int64", "2")}},
+	}
+	desired := []bennyfi.Setting{
+		{Key: "A", Values: []bennyfi.FlexValue{flexValue(t, "int64", "1")}},
+		{Key: "C", Values: []bennyfi.FlexValue{flexValue(t, "int64", "3"), flexValue(t, "int64", "4")}},
+	}
+	plan := bennyfi.PlanSettings(desired, current, false)
+	assert.Equal(t, len(plan), 2, plan.String())
+	assert.Equal(t, plan[0].Action, eos.ActN("setsetting"))
+	assert.Equal(t, plan[1].Action, eos.ActN("appndsetting"))
+
+	plan = bennyfi.PlanSettings(desired, current, true)
+	assert.Equal(t, len(plan), 3, plan.String())
+	assert.Equal(t, plan[2].Action, eos.ActN("erasesetting"))
+	assert.Equal(t, plan[2].Key, "B")
+}
@@ -0,0 +1,75 @@
+//go:build go1.18
+// +build go1.18
+
+package bennyfi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"unicode/utf8"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+)
+
+// FuzzFlexValueBinary checks that any FlexValue that can be decoded is equal to the value parsed from its JSON
+// representation, that it can be encoded, that decoding the encoded bytes results in the same JSON representation
+// and that the encoding is canonical
+func FuzzFlexValueBinary(f *testing.F) {
+	f.Add([]byte{0x00})
+	f.Add([]byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0xea, 0x30, 0x55})
+	f.Add([]byte{0x02, 0x03, 'a', 'b', 'c'})
+	f.Add([]byte{0x05, 0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fv := bennyfi.FlexValue{}
+		if err := eos.UnmarshalBinary(data, &fv); err != nil {
+			return
+		}
+		expectedJSON, err := json.Marshal(&fv)
+		if err != nil {
+			return
+		}
+		if v, ok := fv.Impl.(string); ok && !utf8.ValidString(v) {
+			// invalid UTF-8 is replaced when encoding to JSON
+			return
+		}
+		if v, ok := fv.Impl.(eos.TimePoint); ok && uint64(v) > math.MaxInt64/1000 {
+			// eos-go parses JSON time points through nanoseconds since epoch, which overflow after 2262
+			return
+		}
+		fromJSON := bennyfi.FlexValue{}
+		if err := json.Unmarshal(expectedJSON, &fromJSON); err != nil {
+			t.Fatalf("failed parsing json: %v, error: %v", string(expectedJSON), err)
+		}
+		expected := fv
+		if v, ok := fv.Impl.(eos.TimePoint); ok {
+			// JSON time points have millisecond precision
+			expected.Impl = v - v%1000
+		}
+		if !reflect.DeepEqual(fromJSON, expected) {
+			t.Fatalf("binary and json decoded values differ, binary: %#v, json: %#v", expected, fromJSON)
+		}
+		encoded, err := eos.MarshalBinary(fv)
+		if err != nil {
+			t.Fatalf("failed encoding decoded value: %v, error: %v", string(expectedJSON), err)
+		}
+		actual := bennyfi.FlexValue{}
+		if err := eos.UnmarshalBinary(encoded, &actual); err != nil {
+			t.Fatalf("failed decoding encoded value: %x, error: %v", encoded, err)
+		}
+		actualJSON, err := json.Marshal(&actual)
+		if err != nil {
+			t.Fatalf("failed serializing to json, error: %v", err)
+		}
+		if string(actualJSON) != string(expectedJSON) {
+			t.Fatalf("json mismatch, expected: %v, actual: %v", string(expectedJSON), string(actualJSON))
+		}
+		reencoded, err := eos.MarshalBinary(actual)
+		if err != nil || !bytes.Equal(reencoded, encoded) {
+			t.Fatalf("encoding is not canonical, expected: %x, actual: %x, error: %v", encoded, reencoded, err)
+		}
+	})
+}
@@ -0,0 +1,206 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package bennyfi
+
+import (
+	"fmt"
+
+	eos "github.com/eoscanada/eos-go"
+)
+
+// PlanSettingList computes the minimal sequence of appndsetting and clipsetting actions required for the values
+// of a list setting to change from current to desired. Lists are treated as multisets, the order of the
+// resulting list is not guaranteed to match desired. Appends are done before clips so that the setting is never
+// left empty, and the setting is erased if desired is empty.
+func PlanSettingList(key string, current, desired []FlexValue) SettingsPlan {
+	plan := make(SettingsPlan, 0)
+	if len(desired) == 0 {
+		if len(current) > 0 {
+			plan = append(plan, &SettingOp{
+				Action: "erasesetting",
+				Key:    key,
+			})
+		}
+		return plan
+	}
+	toAdd := subtractFlexValues(desired, current)
+	toRemove := subtractFlexValues(current, desired)
+	for i, value := range toAdd {
+		action := eos.ActN("appndsetting")
+		if i == 0 && len(current) == 0 {
+			action = eos.ActN("setsetting")
+		}
+		plan = append(plan, &SettingOp{
+			Action: action,
+			Key:    key,
+			Value:  value,
+		})
+	}
+	for _, value := range toRemove {
+		plan = append(plan, &SettingOp{
+			Action: "clipsetting",
+			Key:    key,
+			Value:  value,
+		})
+	}
+	return plan
+}
+
+// PlanAddSettingValues computes the actions required to add the values not already in the list
+func PlanAddSettingValues(key string, current []FlexValue, values ...FlexValue) SettingsPlan {
+	desired := append(append([]FlexValue{}, current...), flexValuesFromPointers(subtractFlexValues(values, current))...)
+	return PlanSettingList(key, current, desired)
+}
+
+// PlanRemoveSettingValues computes the actions required to remove the values present in the list, values not
+// in the list are ignored
+func PlanRemoveSettingValues(key string, current []FlexValue, values ...FlexValue) SettingsPlan {
+	return PlanSettingList(key, current, flexValuesFromPointers(subtractFlexValues(current, values)))
+}
+
+// subtractFlexValues returns the values in values1 that are not in values2, taking into account repeated values
+func subtractFlexValues(values1, values2 []FlexValue) []*FlexValue {
+	counts := make(map[string]int, len(values2))
+	for i := range values2 {
+		counts[flexValueKey(&values2[i])]++
+	}
+	result := make([]*FlexValue, 0)
+	for i := range values1 {
+		key := flexValueKey(&values1[i])
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		result = append(result, &values1[i])
+	}
+	return result
+}
+
+// sameFlexValueMultiset evaluates if both lists hold the same values the same number of times in any order
+func sameFlexValueMultiset(values1, values2 []FlexValue) bool {
+	return len(values1) == len(values2) && len(subtractFlexValues(values1, values2)) == 0
+}
+
+func flexValueKey(value *FlexValue) string {
+	return fmt.Sprintf("%v:%v", value.TypeName(), value.String())
+}
+
+func flexValuesFromPointers(values []*FlexValue) []FlexValue {
+	result := make([]FlexValue, 0, len(values))
+	for _, value := range values {
+		result = append(result, *value)
+	}
+	return result
+}
+
+// GetSettingValues returns the values of the setting, an empty list is returned if the setting does not exist
+func (m *BennyfiContract) GetSettingValues(key string) ([]FlexValue, error) {
+	setting, err := m.GetSetting(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting setting: %v, error: %v", key, err)
+	}
+	if setting == nil {
+		return []FlexValue{}, nil
+	}
+	return setting.Values, nil
+}
+
+func (m *BennyfiContract) GetSettingNames(key string) ([]eos.Name, error) {
+	values, err := m.GetSettingValues(key)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]eos.Name, 0, len(values))
+	for i := range values {
+		if values[i].TypeName() != "name" {
+			return nil, fmt.Errorf("setting: %v position: %v, %v", key, i, values[i].invalidTypeError("eos.Name"))
+		}
+		name, err := values[i].Name()
+		if err != nil {
+			return nil, fmt.Errorf("setting: %v position: %v, %v", key, i, err)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (m *BennyfiContract) GetSettingInt64s(key string) ([]int64, error) {
+	values, err := m.GetSettingValues(key)
+	if err != nil {
+		return nil, err
+	}
+	ints := make([]int64, 0, len(values))
+	for i := range values {
+		v, err := values[i].Int64()
+		if err != nil {
+			return nil, fmt.Errorf("setting: %v position: %v, %v", key, i, err)
+		}
+		ints = append(ints, v)
+	}
+	return ints, nil
+}
+
+func (m *BennyfiContract) GetSettingAssets(key string) ([]eos.Asset, error) {
+	values, err := m.GetSettingValues(key)
+	if err != nil {
+		return nil, err
+	}
+	assets := make([]eos.Asset, 0, len(values))
+	for i := range values {
+		v, err := values[i].Asset()
+		if err != nil {
+			return nil, fmt.Errorf("setting: %v position: %v, %v", key, i, err)
+		}
+		assets = append(assets, v)
+	}
+	return assets, nil
+}
+
+// AddSettingValues appends the values that are not already in the list setting, returns the executed plan
+func (m *BennyfiContract) AddSettingValues(owner eos.AccountName, key string, values ...FlexValue) (SettingsPlan, error) {
+	current, err := m.GetSettingValues(key)
+	if err != nil {
+		return nil, err
+	}
+	plan := PlanAddSettingValues(key, current, values...)
+	return plan, m.ApplySettingsPlan(owner, plan)
+}
+
+// RemoveSettingValues clips the values that are in the list setting, returns the executed plan
+func (m *BennyfiContract) RemoveSettingValues(owner eos.AccountName, key string, values ...FlexValue) (SettingsPlan, error) {
+	current, err := m.GetSettingValues(key)
+	if err != nil {
+		return nil, err
+	}
+	plan := PlanRemoveSettingValues(key, current, values...)
+	return plan, m.ApplySettingsPlan(owner, plan)
+}
+
+// ReplaceSettingValues converges the list setting to the specified values, returns the executed plan
+func (m *BennyfiContract) ReplaceSettingValues(owner eos.AccountName, key string, values ...FlexValue) (SettingsPlan, error) {
+	current, err := m.GetSettingValues(key)
+	if err != nil {
+		return nil, err
+	}
+	plan := PlanSettingList(key, current, values)
+	return plan, m.ApplySettingsPlan(owner, plan)
+}
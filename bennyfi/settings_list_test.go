@@ -0,0 +1,65 @@
+package bennyfi_test
+
+import (
+	"testing"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+	"gotest.tools/assert"
+)
+
+func names(t *testing.T, values ...string) []bennyfi.FlexValue {
+	result := make([]bennyfi.FlexValue, 0, len(values))
+	for _, value := range values {
+		result = append(result, flexValue(t, "name", value))
+	}
+	return result
+}
+
+func TestPlanSettingList(t *testing.T) {
+	plan := bennyfi.PlanSettingList("ALLOWED", names(t, "alice", "bob", "carol"), names(t, "carol", "dave", "alice"))
+	assert.Equal(t, len(plan), 2, plan.String())
+	assert.Equal(t, plan[0].Action, eos.ActN("appndsetting"))
+	assert.Equal(t, plan[0].Value.String(), "dave")
+	assert.Equal(t, plan[1].Action, eos.ActN("clipsetting"))
+	assert.Equal(t, plan[1].Value.String(), "bob")
+
+	plan = bennyfi.PlanSettingList("ALLOWED", nil, names(t, "alice", "bob"))
+	assert.Equal(t, len(plan), 2, plan.String())
+	assert.Equal(t, plan[0].Action, eos.ActN("setsetting"))
+	assert.Equal(t, plan[1].Action, eos.ActN("appndsetting"))
+
+	plan = bennyfi.PlanSettingList("ALLOWED", names(t, "alice"), nil)
+	assert.Equal(t, len(plan), 1, plan.String())
+	assert.Equal(t, plan[0].Action, eos.ActN("erasesetting"))
+
+	assert.Equal(t, len(bennyfi.PlanSettingList("ALLOWED", names(t, "alice", "bob"), names(t, "bob", "alice"))), 0)
+}
+
+func TestPlanAddRemoveSettingValues(t *testing.T) {
+	current := names(t, "alice", "bob")
+	plan := bennyfi.PlanAddSettingValues("ALLOWED", current, names(t, "bob", "carol")...)
+	assert.Equal(t, len(plan), 1, plan.String())
+	assert.Equal(t, plan[0].Action, eos.ActN("appndsetting"))
+	assert.Equal(t, plan[0].Value.String(), "carol")
+
+	plan = bennyfi.PlanRemoveSettingValues("ALLOWED", current, names(t, "bob", "carol")...)
+	assert.Equal(t, len(plan), 1, plan.String())
+	assert.Equal(t, plan[0].Action, eos.ActN("clipsetting"))
+	assert.Equal(t, plan[0].Value.String(), "bob")
+
+	plan = bennyfi.PlanRemoveSettingValues("ALLOWED", current, current...)
+	assert.Equal(t, len(plan), 1, plan.String())
+	assert.Equal(t, plan[0].Action, eos.ActN("erasesetting"))
+}
+
+func TestPlanSettingValuesIgnoresOrder(t *testing.T) {
+	assert.Equal(t, len(bennyfi.PlanSettingValues("ALLOWED", names(t, "alice", "bob"), names(t, "bob", "alice"))), 0)
+	assert.Equal(t, len(bennyfi.PlanSettingValues("ALLOWED", names(t, "alice", "alice"), names(t, "alice"))), 1)
+	plan := bennyfi.PlanSettings(
+		[]bennyfi.Setting{{Key: "ALLOWED", Values: names(t, "carol", "alice")}},
+		[]bennyfi.Setting{{Key: "ALLOWED", Values: names(t, "alice", "carol")}},
+		true,
+	)
+	assert.Equal(t, len(plan), 0, plan.String())
+}
@@ -0,0 +1,230 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package bennyfi
+
+import (
+	"fmt"
+
+	eos "github.com/eoscanada/eos-go"
+)
+
+// SettingDefinition describes the expected FlexValue type of a setting, whether it can hold multiple values,
+// and any additional validation rules for its values
+type SettingDefinition struct {
+	Key         string
+	Type        string
+	MultiValued bool
+	Validate    func(value *FlexValue) error
+}
+
+func (m *SettingDefinition) ValidateValue(value *FlexValue) error {
+	if value == nil {
+		return fmt.Errorf("setting: %v requires a value", m.Key)
+	}
+	if typeName := value.TypeName(); typeName != m.Type {
+		return fmt.Errorf("invalid type for setting: %v, expected: %v, found: %v", m.Key, m.Type, typeName)
+	}
+	if m.Validate != nil {
+		if err := m.Validate(value); err != nil {
+			return fmt.Errorf("invalid value: %v for setting: %v, error: %v", value, m.Key, err)
+		}
+	}
+	return nil
+}
+
+// SettingsRegistry holds the definitions of the known setting keys, settings not in the registry are
+// considered free-form and are not validated
+type SettingsRegistry map[string]*SettingDefinition
+
+func NewSettingsRegistry(definitions ...*SettingDefinition) SettingsRegistry {
+	registry := make(SettingsRegistry, len(definitions))
+	for _, definition := range definitions {
+		registry.Register(definition)
+	}
+	return registry
+}
+
+// DefaultSettingsRegistry only defines the settings the client reads, the rest of the contract settings are
+// not registered and are accepted with any value, register their definitions to have them validated
+func DefaultSettingsRegistry() SettingsRegistry {
+	return NewSettingsRegistry(
+		&SettingDefinition{
+			Key:      SettingVRFContract,
+			Type:     "name",
+			Validate: ValidateAccountName,
+		},
+	)
+}
+
+func (m SettingsRegistry) Register(definition *SettingDefinition) {
+	m[definition.Key] = definition
+}
+
+func (m SettingsRegistry) Get(key string) *SettingDefinition {
+	return m[key]
+}
+
+// ValidateSet validates the value to be set by the setsetting action, values of unknown keys are not checked
+func (m SettingsRegistry) ValidateSet(key string, value *FlexValue) error {
+	definition := m.Get(key)
+	if definition == nil {
+		return nil
+	}
+	return definition.ValidateValue(value)
+}
+
+// ValidateAppend validates the value to be appended by the appndsetting action, values of unknown keys are not
+// checked
+func (m SettingsRegistry) ValidateAppend(key string, value *FlexValue) error {
+	definition := m.Get(key)
+	if definition == nil {
+		return nil
+	}
+	if !definition.MultiValued {
+		return fmt.Errorf("setting: %v is single valued, values can not be appended", key)
+	}
+	return definition.ValidateValue(value)
+}
+
+// ValidateSetting validates the number and the values of the setting
+func (m SettingsRegistry) ValidateSetting(setting *Setting) error {
+	definition := m.Get(setting.Key)
+	if definition == nil {
+		return nil
+	}
+	if len(setting.Values) == 0 {
+		return fmt.Errorf("setting: %v requires at least one value", setting.Key)
+	}
+	if !definition.MultiValued && len(setting.Values) > 1 {
+		return fmt.Errorf("setting: %v is single valued, found: %v values", setting.Key, len(setting.Values))
+	}
+	for i := range setting.Values {
+		if err := definition.ValidateValue(&setting.Values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m SettingsRegistry) validateAction(action eos.ActionName, key string, value *FlexValue) error {
+	switch action {
+	case "setsetting":
+		return m.ValidateSet(key, value)
+	case "appndsetting":
+		return m.ValidateAppend(key, value)
+	default:
+		if definition := m.Get(key); definition != nil && value != nil && value.TypeName() != definition.Type {
+			return fmt.Errorf("invalid type for setting: %v, expected: %v, found: %v", key, definition.Type, value.TypeName())
+		}
+		return nil
+	}
+}
+
+func ValidateAccountName(value *FlexValue) error {
+	name, err := value.Name()
+	if err != nil {
+		return err
+	}
+	if len(name) == 0 || len(name) > 12 {
+		return fmt.Errorf("account name must have between 1 and 12 characters")
+	}
+	nameValue, err := eos.StringToName(string(name))
+	if err != nil || eos.NameToString(nameValue) != string(name) {
+		return fmt.Errorf("invalid account name: %v", name)
+	}
+	return nil
+}
+
+func ValidateNonNegative(value *FlexValue) error {
+	switch v := value.Impl.(type) {
+	case int64:
+		if v < 0 {
+			return fmt.Errorf("value must not be negative")
+		}
+	case eos.Asset:
+		if v.Amount < 0 {
+			return fmt.Errorf("value must not be negative")
+		}
+	}
+	return nil
+}
+
+func (m *BennyfiContract) getSingleSettingValue(key string) (*FlexValue, error) {
+	setting, err := m.GetSetting(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting setting: %v, error: %v", key, err)
+	}
+	if setting == nil || len(setting.Values) == 0 {
+		return nil, fmt.Errorf("setting: %v not found", key)
+	}
+	if len(setting.Values) > 1 {
+		return nil, fmt.Errorf("setting: %v is multi valued, found: %v values", key, len(setting.Values))
+	}
+	return &setting.Values[0], nil
+}
+
+func (m *BennyfiContract) GetSettingName(key string) (eos.Name, error) {
+	value, err := m.getSingleSettingValue(key)
+	if err != nil {
+		return "", err
+	}
+	if value.TypeName() != "name" {
+		return "", fmt.Errorf("setting: %v, %w", key, value.invalidTypeError("eos.Name"))
+	}
+	return value.Name()
+}
+
+func (m *BennyfiContract) GetSettingInt64(key string) (int64, error) {
+	value, err := m.getSingleSettingValue(key)
+	if err != nil {
+		return 0, err
+	}
+	v, err := value.Int64()
+	if err != nil {
+		return 0, fmt.Errorf("setting: %v, %w", key, err)
+	}
+	return v, nil
+}
+
+func (m *BennyfiContract) GetSettingAsset(key string) (eos.Asset, error) {
+	value, err := m.getSingleSettingValue(key)
+	if err != nil {
+		return eos.Asset{}, err
+	}
+	v, err := value.Asset()
+	if err != nil {
+		return eos.Asset{}, fmt.Errorf("setting: %v, %w", key, err)
+	}
+	return v, nil
+}
+
+func (m *BennyfiContract) GetSettingTimePoint(key string) (eos.TimePoint, error) {
+	value, err := m.getSingleSettingValue(key)
+	if err != nil {
+		return 0, err
+	}
+	v, err := value.TimePoint()
+	if err != nil {
+		return 0, fmt.Errorf("setting: %v, %w", key, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,75 @@
+package bennyfi_test
+
+import (
+	"errors"
+	"testing"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+	"gotest.tools/assert"
+)
+
+func TestSettingsRegistry(t *testing.T) {
+	registry := bennyfi.DefaultSettingsRegistry()
+	registry.Register(&bennyfi.SettingDefinition{
+		Key:         "ALLOWED_ACCOUNTS",
+		Type:        "name",
+		MultiValued: true,
+	})
+
+	vrf, err := bennyfi.StringToSetting("name", "vrf.contract")
+	assert.NilError(t, err)
+	assert.NilError(t, registry.ValidateSet(bennyfi.SettingVRFContract, &vrf))
+	assert.ErrorContains(t, registry.ValidateAppend(bennyfi.SettingVRFContract, &vrf), "single valued")
+
+	invalidName, err := bennyfi.StringToSetting("name", "Invalid.Name")
+	assert.NilError(t, err)
+	assert.ErrorContains(t, registry.ValidateSet(bennyfi.SettingVRFContract, &invalidName), "invalid account name")
+
+	intValue, err := bennyfi.StringToSetting("int64", "10")
+	assert.NilError(t, err)
+	assert.ErrorContains(t, registry.ValidateSet(bennyfi.SettingVRFContract, &intValue), "expected: name, found: int64")
+	assert.NilError(t, registry.ValidateSet("FREE_FORM", &intValue))
+
+	assert.NilError(t, registry.ValidateAppend("ALLOWED_ACCOUNTS", &vrf))
+	assert.ErrorContains(t, registry.ValidateSetting(&bennyfi.Setting{
+		Key:    bennyfi.SettingVRFContract,
+		Values: []bennyfi.FlexValue{vrf, vrf},
+	}), "single valued")
+	assert.NilError(t, registry.ValidateSetting(&bennyfi.Setting{
+		Key:    "ALLOWED_ACCOUNTS",
+		Values: []bennyfi.FlexValue{vrf, vrf},
+	}))
+}
+
+func TestFlexValueInvalidTypeError(t *testing.T) {
+	value, err := bennyfi.StringToSetting("name", "account")
+	assert.NilError(t, err)
+	assert.Equal(t, value.TypeName(), "name")
+	_, err = value.Asset()
+	assert.ErrorContains(t, err, "expected type: eos.Asset")
+	name, err := value.Name()
+	assert.NilError(t, err)
+	assert.Equal(t, name, eos.Name("account"))
+}
+
+func TestFlexValueInt64(t *testing.T) {
+	value, err := bennyfi.StringToSetting("int64", "10")
+	assert.NilError(t, err)
+	v, err := value.Int64()
+	assert.NilError(t, err)
+	assert.Equal(t, v, int64(10))
+
+	monostate, err := bennyfi.StringToSetting("monostate", "")
+	assert.NilError(t, err)
+	_, err = monostate.Int64()
+	assert.ErrorContains(t, err, "received an unexpected type monostate")
+	assert.ErrorContains(t, err, "expected type: int64")
+
+	timePoint, err := bennyfi.StringToSetting("time_point", "10")
+	assert.NilError(t, err)
+	_, err = timePoint.Int64()
+	var typeErr *bennyfi.InvalidTypeError
+	assert.Assert(t, errors.As(err, &typeErr))
+	assert.Equal(t, typeErr.ExpectedType, "int64")
+}
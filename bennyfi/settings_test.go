@@ -0,0 +1,163 @@
+package bennyfi_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+	"gotest.tools/assert"
+)
+
+const maxTimePoint = 253402300799999999 // 9999-12-31T23:59:59.999999Z
+
+func randomFlexValue(typeName string, r *rand.Rand) bennyfi.FlexValue {
+	var impl interface{}
+	switch typeName {
+	case "monostate":
+		impl = int64(0)
+	case "name":
+		impl = eos.Name(eos.NameToString(r.Uint64()))
+	case "string":
+		runes := make([]rune, r.Intn(20))
+		for i := range runes {
+			runes[i] = rune(r.Intn(0xD7FF) + 1)
+		}
+		impl = string(runes)
+	case "asset":
+		symbol := make([]byte, r.Intn(7)+1)
+		for i := range symbol {
+			symbol[i] = byte('A' + r.Intn(26))
+		}
+		amount := r.Int63n(1 << 62)
+		if r.Intn(2) == 0 {
+			amount = -amount
+		}
+		impl = eos.Asset{
+			Amount: eos.Int64(amount),
+			Symbol: eos.Symbol{Precision: uint8(r.Intn(19)), Symbol: string(symbol)},
+		}
+	case "time_point":
+		impl = eos.TimePoint(r.Int63n(maxTimePoint + 1))
+	case "int64":
+		impl = int64(r.Uint64())
+	case "checksum256":
+		checksum := make([]byte, 32)
+		r.Read(checksum)
+		impl = eos.Checksum256(checksum)
+	}
+	return bennyfi.FlexValue{
+		BaseVariant: eos.BaseVariant{
+			TypeID: bennyfi.GetVariants().TypeID(typeName),
+			Impl:   impl,
+		},
+	}
+}
+
+func TestFlexValueStringRoundTrip(t *testing.T) {
+	typeNames := []string{"monostate", "name", "string", "asset", "time_point", "int64", "checksum256"}
+	for _, typeName := range typeNames {
+		typeName := typeName
+		t.Run(typeName, func(t *testing.T) {
+			roundTrip := func(seed int64) bool {
+				expected := randomFlexValue(typeName, rand.New(rand.NewSource(seed)))
+				actual, err := bennyfi.StringToSetting(typeName, expected.String())
+				if err != nil {
+					t.Logf("failed parsing: %q, error: %v", expected.String(), err)
+					return false
+				}
+				return actual.TypeID == expected.TypeID && reflect.DeepEqual(actual.Impl, expected.Impl)
+			}
+			assert.NilError(t, quick.Check(roundTrip, &quick.Config{MaxCount: 1000}))
+		})
+	}
+}
+
+func TestStringToSettingTimePoint(t *testing.T) {
+	expected := eos.TimePoint(1609459200000000)
+	for _, input := range []string{"2021-01-01T00:00:00Z", "2021-01-01T02:00:00+02:00", "2021-01-01T00:00:00.000", "1609459200"} {
+		fv, err := bennyfi.StringToSetting("timepoint", input)
+		assert.NilError(t, err)
+		tp, err := fv.TimePoint()
+		assert.NilError(t, err)
+		assert.Equal(t, tp, expected, input)
+	}
+	_, err := bennyfi.StringToSetting("time_point", "yesterday")
+	assert.ErrorContains(t, err, "invalid time point")
+}
+
+func TestStringToSettingChecksum256(t *testing.T) {
+	input := strings.Repeat("ab", 32)
+	fv, err := bennyfi.StringToSetting("checksum256", input)
+	assert.NilError(t, err)
+	assert.Equal(t, fv.String(), input)
+
+	_, err = bennyfi.StringToSetting("checksum256", "abcd")
+	assert.ErrorContains(t, err, "expected 32 bytes")
+	_, err = bennyfi.StringToSetting("checksum256", "xyz")
+	assert.ErrorContains(t, err, "cannot convert settings value to checksum256")
+	_, err = bennyfi.StringToSetting("monostate", "1")
+	assert.ErrorContains(t, err, "must be empty")
+	_, err = bennyfi.StringToSetting("int64", "1"+strings.Repeat("0", int(math.Log10(math.MaxInt64))+1))
+	assert.ErrorContains(t, err, "cannot convert settings value to int64")
+}
+
+func TestFlexValueBinaryRoundTrip(t *testing.T) {
+	typeNames := []string{"monostate", "name", "string", "asset", "time_point", "int64", "checksum256"}
+	for _, typeName := range typeNames {
+		typeName := typeName
+		t.Run(typeName, func(t *testing.T) {
+			roundTrip := func(seed int64) bool {
+				expected := randomFlexValue(typeName, rand.New(rand.NewSource(seed)))
+				data, err := eos.MarshalBinary(expected)
+				if err != nil {
+					t.Logf("failed encoding: %v, error: %v", expected.String(), err)
+					return false
+				}
+				actual := bennyfi.FlexValue{}
+				err = eos.UnmarshalBinary(data, &actual)
+				if err != nil {
+					t.Logf("failed decoding: %x, error: %v", data, err)
+					return false
+				}
+				expectedJSON, _ := json.Marshal(&expected)
+				actualJSON, _ := json.Marshal(&actual)
+				return actual.TypeID == expected.TypeID && string(actualJSON) == string(expectedJSON)
+			}
+			assert.NilError(t, quick.Check(roundTrip, &quick.Config{MaxCount: 1000}))
+		})
+	}
+}
+
+func TestFlexValueBinary(t *testing.T) {
+	data, err := eos.MarshalBinary(randomFlexValue("monostate", nil))
+	assert.NilError(t, err)
+	assert.Equal(t, hex.EncodeToString(data), "00")
+
+	fv, err := bennyfi.StringToSetting("int64", "5")
+	assert.NilError(t, err)
+	data, err = eos.MarshalBinary(&fv)
+	assert.NilError(t, err)
+	assert.Equal(t, hex.EncodeToString(data), "050500000000000000")
+
+	err = eos.UnmarshalBinary([]byte{0x07}, &bennyfi.FlexValue{})
+	assert.ErrorContains(t, err, "unknown flex value type id")
+	err = eos.UnmarshalBinary([]byte{0x02, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}, &bennyfi.FlexValue{})
+	assert.ErrorContains(t, err, "unable to decode flex value of type: string")
+	err = eos.UnmarshalBinary([]byte{0x03, 0x01, 0, 0, 0, 0, 0, 0, 0, 0x13, 'T', 'L', 'O', 'S', 0, 0, 0}, &bennyfi.FlexValue{})
+	assert.ErrorContains(t, err, "asset precision must be at most 18")
+	err = eos.UnmarshalBinary([]byte{0x03, 0x01, 0, 0, 0, 0, 0, 0, 0, 0x04, 't', 'l', 'o', 's', 0, 0, 0}, &bennyfi.FlexValue{})
+	assert.ErrorContains(t, err, "must only contain upper case letters")
+	fv = bennyfi.FlexValue{BaseVariant: eos.BaseVariant{
+		TypeID: bennyfi.GetVariants().TypeID("asset"),
+		Impl:   eos.Asset{Amount: 1 << 62, Symbol: eos.Symbol{Precision: 4, Symbol: "TLOS"}},
+	}}
+	_, err = eos.MarshalBinary(fv)
+	assert.ErrorContains(t, err, "asset amount out of range")
+}
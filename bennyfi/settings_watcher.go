@@ -0,0 +1,296 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package bennyfi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+)
+
+type SettingChangeType string
+
+const (
+	SettingAdded   SettingChangeType = "added"
+	SettingChanged SettingChangeType = "changed"
+	SettingErased  SettingChangeType = "erased"
+)
+
+// SettingChange is published by the SettingsWatcher, OldValues is empty for added settings and NewValues
+// is empty for erased settings. UpdatedDate is the updated date of the setting row on chain, it is zero for
+// erased settings as the row no longer exists.
+type SettingChange struct {
+	Type        SettingChangeType  `json:"type"`
+	Key         string             `json:"key"`
+	OldValues   []FlexValue        `json:"old_values"`
+	NewValues   []FlexValue        `json:"new_values"`
+	UpdatedDate eos.BlockTimestamp `json:"updated_date"`
+	DetectedAt  time.Time          `json:"detected_at"`
+}
+
+func (m *SettingChange) String() string {
+	return fmt.Sprintf("%v setting: %v %v, updated: %v, old: %v, new: %v", m.DetectedAt.UTC().Format(time.RFC3339), m.Key, m.Type, m.UpdatedDate, flexValuesString(m.OldValues), flexValuesString(m.NewValues))
+}
+
+func flexValuesString(values []FlexValue) string {
+	strs := make([]string, 0, len(values))
+	for i := range values {
+		strs = append(strs, fmt.Sprintf("%v(%v)", values[i].TypeName(), values[i].String()))
+	}
+	return fmt.Sprintf("[%v]", strings.Join(strs, ", "))
+}
+
+// DiffSettings returns the changes required to go from the old to the new settings sorted by key
+func DiffSettings(old, new []Setting, detectedAt time.Time) []*SettingChange {
+	oldSettings := make(map[string]*Setting, len(old))
+	for i := range old {
+		oldSettings[old[i].Key] = &old[i]
+	}
+	newSettings := make(map[string]*Setting, len(new))
+	for i := range new {
+		newSettings[new[i].Key] = &new[i]
+	}
+	changes := make([]*SettingChange, 0)
+	for key, newSetting := range newSettings {
+		oldSetting, ok := oldSettings[key]
+		if !ok {
+			changes = append(changes, &SettingChange{
+				Type:        SettingAdded,
+				Key:         key,
+				NewValues:   newSetting.Values,
+				UpdatedDate: newSetting.UpdatedDate,
+				DetectedAt:  detectedAt,
+			})
+		} else if !sameFlexValues(oldSetting.Values, newSetting.Values) {
+			changes = append(changes, &SettingChange{
+				Type:        SettingChanged,
+				Key:         key,
+				OldValues:   oldSetting.Values,
+				NewValues:   newSetting.Values,
+				UpdatedDate: newSetting.UpdatedDate,
+				DetectedAt:  detectedAt,
+			})
+		}
+	}
+	for key, oldSetting := range oldSettings {
+		if _, ok := newSettings[key]; !ok {
+			changes = append(changes, &SettingChange{
+				Type:       SettingErased,
+				Key:        key,
+				OldValues:  oldSetting.Values,
+				DetectedAt: detectedAt,
+			})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+type SettingsSource interface {
+	GetAllSettings() ([]Setting, error)
+}
+
+// SettingsWatcher polls the settings table and publishes the changes to its subscribers, the first poll
+// establishes the baseline and does not publish any changes. Publishing never blocks, a change is dropped for
+// a subscriber whose buffer is full and OnDrop is called, so subscribers should use a buffer and consume
+// changes promptly. If LogFile is set the changes are also appended to it as JSON lines.
+type SettingsWatcher struct {
+	Source   SettingsSource
+	Interval time.Duration
+	LogFile  string
+	// OnError is called when polling fails, by default the error is logged
+	OnError func(error)
+	// OnDrop is called when a change could not be delivered to a subscriber, by default the change is logged
+	OnDrop func(*SettingChange)
+
+	mutex       sync.Mutex
+	current     []Setting
+	initialized bool
+	nextID      int
+	subscribers map[int]chan *SettingChange
+}
+
+func NewSettingsWatcher(source SettingsSource, interval time.Duration) *SettingsWatcher {
+	return &SettingsWatcher{
+		Source:   source,
+		Interval: interval,
+		OnError: func(err error) {
+			log.Println("Failed polling settings, error: ", err)
+		},
+		OnDrop: func(change *SettingChange) {
+			log.Println("Subscriber buffer full, dropped setting change: ", change)
+		},
+		subscribers: make(map[int]chan *SettingChange),
+	}
+}
+
+// Subscribe returns a channel on which changes are published and a function to unsubscribe, the channel is
+// closed on unsubscribe
+func (m *SettingsWatcher) Subscribe(buffer int) (<-chan *SettingChange, func()) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	id := m.nextID
+	m.nextID++
+	ch := make(chan *SettingChange, buffer)
+	m.subscribers[id] = ch
+	return ch, func() {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		if ch, ok := m.subscribers[id]; ok {
+			delete(m.subscribers, id)
+			close(ch)
+		}
+	}
+}
+
+// Current returns the settings as of the last successful poll
+func (m *SettingsWatcher) Current() []Setting {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.current
+}
+
+// Poll fetches the settings and publishes the changes since the last poll, returns the published changes. The
+// changes are published even if they can not be written to the log file, in which case the error is returned
+func (m *SettingsWatcher) Poll(ctx context.Context) ([]*SettingChange, error) {
+	settings, err := m.Source.GetAllSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed getting settings, error: %v", err)
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	changes := make([]*SettingChange, 0)
+	if m.initialized {
+		changes = DiffSettings(m.current, settings, time.Now())
+	}
+	m.current = settings
+	m.initialized = true
+	for _, change := range changes {
+		for _, subscriber := range m.subscribers {
+			select {
+			case subscriber <- change:
+			default:
+				if m.OnDrop != nil {
+					m.OnDrop(change)
+				}
+			}
+		}
+	}
+	// the changes are published before they are logged, so a log failure does not lose them
+	if m.LogFile != "" && len(changes) > 0 {
+		err = AppendSettingChangeLog(m.LogFile, changes)
+		if err != nil {
+			return changes, err
+		}
+	}
+	return changes, nil
+}
+
+// Run polls the settings at the configured interval until the context is cancelled
+func (m *SettingsWatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+	for {
+		_, err := m.Poll(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			m.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// AppendSettingChangeLog appends the changes to the log file as JSON lines
+func AppendSettingChangeLog(file string, changes []*SettingChange) error {
+	data := make([]byte, 0)
+	for _, change := range changes {
+		line, err := json.Marshal(change)
+		if err != nil {
+			return fmt.Errorf("failed serializing setting change, error: %v", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed opening setting change log: %v, error: %v", file, err)
+	}
+	defer f.Close()
+	_, err = f.Write(data)
+	if err != nil {
+		return fmt.Errorf("failed writing setting change log: %v, error: %v", file, err)
+	}
+	return nil
+}
+
+// LoadSettingChangeLog reads the setting changes from the log file
+func LoadSettingChangeLog(file string) ([]*SettingChange, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading setting change log: %v, error: %v", file, err)
+	}
+	changes := make([]*SettingChange, 0)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for decoder.More() {
+		change := &SettingChange{}
+		err = decoder.Decode(change)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing setting change log: %v, error: %v", file, err)
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// sameFlexValues evaluates if both lists hold the same values in the same order, the watcher mirrors the
+// table so a reordering of a list setting is reported as a change
+func sameFlexValues(values1, values2 []FlexValue) bool {
+	if len(values1) != len(values2) {
+		return false
+	}
+	for i := range values1 {
+		if values1[i].TypeID != values2[i].TypeID || values1[i].String() != values2[i].String() {
+			return false
+		}
+	}
+	return true
+}
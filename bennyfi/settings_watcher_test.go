@@ -0,0 +1,161 @@
+package bennyfi_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+	"gotest.tools/assert"
+)
+
+type settingsSourceMock struct {
+	settings []bennyfi.Setting
+}
+
+func (m *settingsSourceMock) GetAllSettings() ([]bennyfi.Setting, error) {
+	return m.settings, nil
+}
+
+func TestSettingsWatcher(t *testing.T) {
+	source := &settingsSourceMock{
+		settings: []bennyfi.Setting{
+			{Key: "VRF_CONTRACT", Values: names(t, "orng.wax")},
+			{Key: "ALLOWED", Values: names(t, "alice")},
+		},
+	}
+	watcher := bennyfi.NewSettingsWatcher(source, time.Second)
+	changesCh, unsubscribe := watcher.Subscribe(10)
+
+	changes, err := watcher.Poll(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, len(changes), 0)
+
+	updatedDate := eos.BlockTimestamp{Time: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)}
+	source.settings = []bennyfi.Setting{
+		{Key: "VRF_CONTRACT", Values: names(t, "vrf.wax"), UpdatedDate: updatedDate},
+		{Key: "FEE", Values: []bennyfi.FlexValue{flexValue(t, "int64", "5")}, UpdatedDate: updatedDate},
+	}
+	changes, err = watcher.Poll(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, len(changes), 3)
+	expected := []struct {
+		changeType bennyfi.SettingChangeType
+		key        string
+	}{
+		{bennyfi.SettingErased, "ALLOWED"},
+		{bennyfi.SettingAdded, "FEE"},
+		{bennyfi.SettingChanged, "VRF_CONTRACT"},
+	}
+	for _, e := range expected {
+		change := <-changesCh
+		assert.Equal(t, change.Type, e.changeType)
+		assert.Equal(t, change.Key, e.key)
+	}
+	assert.Equal(t, changes[2].OldValues[0].String(), "orng.wax")
+	assert.Equal(t, changes[2].NewValues[0].String(), "vrf.wax")
+	assert.Equal(t, changes[2].UpdatedDate, updatedDate)
+	assert.Assert(t, changes[0].UpdatedDate.IsZero())
+
+	unsubscribe()
+	_, ok := <-changesCh
+	assert.Assert(t, !ok)
+}
+
+func TestSettingsWatcherDoesNotBlock(t *testing.T) {
+	source := &settingsSourceMock{
+		settings: []bennyfi.Setting{{Key: "ALLOWED", Values: names(t, "alice")}},
+	}
+	watcher := bennyfi.NewSettingsWatcher(source, time.Second)
+	dropped := make([]*bennyfi.SettingChange, 0)
+	watcher.OnDrop = func(change *bennyfi.SettingChange) {
+		dropped = append(dropped, change)
+	}
+	_, unsubscribeFull := watcher.Subscribe(0)
+	changesCh, unsubscribe := watcher.Subscribe(1)
+	_, err := watcher.Poll(context.Background())
+	assert.NilError(t, err)
+
+	source.settings = []bennyfi.Setting{{Key: "ALLOWED", Values: names(t, "bob")}}
+	changes, err := watcher.Poll(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, len(changes), 1)
+	assert.Equal(t, len(dropped), 1)
+	assert.Equal(t, (<-changesCh).Key, "ALLOWED")
+
+	done := make(chan bool)
+	go func() {
+		unsubscribeFull()
+		unsubscribe()
+		done <- true
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("unsubscribe blocked")
+	}
+}
+
+func TestSettingChangeLog(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "settings.log")
+	source := &settingsSourceMock{
+		settings: []bennyfi.Setting{{Key: "ALLOWED", Values: names(t, "alice")}},
+	}
+	watcher := bennyfi.NewSettingsWatcher(source, time.Second)
+	watcher.LogFile = logFile
+	_, err := watcher.Poll(context.Background())
+	assert.NilError(t, err)
+
+	updatedDate := eos.BlockTimestamp{Time: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)}
+	source.settings = []bennyfi.Setting{
+		{Key: "ALLOWED", Values: names(t, "alice", "bob"), UpdatedDate: updatedDate},
+		{Key: "FEE", Values: []bennyfi.FlexValue{flexValue(t, "int64", "5")}, UpdatedDate: updatedDate},
+	}
+	_, err = watcher.Poll(context.Background())
+	assert.NilError(t, err)
+	source.settings = source.settings[:1]
+	_, err = watcher.Poll(context.Background())
+	assert.NilError(t, err)
+
+	changes, err := bennyfi.LoadSettingChangeLog(logFile)
+	assert.NilError(t, err)
+	assert.Equal(t, len(changes), 3)
+	assert.Equal(t, changes[0].Type, bennyfi.SettingChanged)
+	assert.Equal(t, changes[0].Key, "ALLOWED")
+	assert.Equal(t, flexValuesString(changes[0].NewValues), "alice,bob")
+	assert.Equal(t, changes[0].UpdatedDate.Unix(), updatedDate.Unix())
+	assert.Equal(t, changes[1].Type, bennyfi.SettingAdded)
+	assert.Equal(t, changes[1].NewValues[0].TypeName(), "int64")
+	assert.Equal(t, changes[2].Type, bennyfi.SettingErased)
+	assert.Equal(t, changes[2].Key, "FEE")
+}
+
+func TestSettingsWatcherPublishesWhenLogFails(t *testing.T) {
+	source := &settingsSourceMock{
+		settings: []bennyfi.Setting{{Key: "ALLOWED", Values: names(t, "alice")}},
+	}
+	watcher := bennyfi.NewSettingsWatcher(source, time.Second)
+	watcher.LogFile = filepath.Join(t.TempDir(), "missing", "settings.log")
+	changesCh, unsubscribe := watcher.Subscribe(1)
+	defer unsubscribe()
+	_, err := watcher.Poll(context.Background())
+	assert.NilError(t, err)
+
+	source.settings = []bennyfi.Setting{{Key: "ALLOWED", Values: names(t, "bob")}}
+	changes, err := watcher.Poll(context.Background())
+	assert.ErrorContains(t, err, "failed opening setting change log")
+	assert.Equal(t, len(changes), 1)
+	change := <-changesCh
+	assert.Equal(t, flexValuesString(change.NewValues), "bob")
+}
+
+func flexValuesString(values []bennyfi.FlexValue) string {
+	strs := make([]string, 0, len(values))
+	for i := range values {
+		strs = append(strs, values[i].String())
+	}
+	return strings.Join(strs, ",")
+}
@@ -0,0 +1,432 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package bennyfi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	eos "github.com/eoscanada/eos-go"
+)
+
+var (
+	// RewardHeldStates are the states in which the total reward of a manager funded round is held in the
+	// staked balance of the round manager
+	RewardHeldStates = []eos.Name{RoundAcceptingEntries, RoundDrawing, RoundOpen, RoundClosed}
+	// RexHeldStates are the rex states in which the deposits of a rex pool round are not held by the
+	// bennyfi contract on the token contract
+	RexHeldStates = []eos.Name{RexStateInSavings, RexStateInLockPeriod, RexStateSold}
+)
+
+// SymbolSolvency holds the reconciliation results for a token, LedgerTotal is the sum of all liquid and staked
+// balances, ExpectedStaked is the sum of the entry stakes of staked entries plus the rewards held
+type SymbolSolvency struct {
+	Symbol          eos.Symbol
+	TokenContract   eos.AccountName
+	LiquidTotal     eos.Asset
+	StakedTotal     eos.Asset
+	LedgerTotal     eos.Asset
+	ExpectedStaked  eos.Asset
+	HeldInRex       eos.Asset
+	ContractBalance eos.Asset
+	Discrepancies   []string
+}
+
+func (m *SymbolSolvency) String() string {
+	status := "OK"
+	if len(m.Discrepancies) > 0 {
+		status = strings.Join(m.Discrepancies, "; ")
+	}
+	return fmt.Sprintf("%v@%v ledger: %v (liquid: %v, staked: %v), expected staked: %v, held in rex: %v, contract balance: %v, status: %v",
+		m.Symbol.Symbol, m.TokenContract, m.LedgerTotal, m.LiquidTotal, m.StakedTotal, m.ExpectedStaked, m.HeldInRex, m.ContractBalance, status)
+}
+
+type HolderDiscrepancy struct {
+	TokenHolder    eos.AccountName
+	Symbol         eos.Symbol
+	StakedBalance  eos.Asset
+	ExpectedStaked eos.Asset
+}
+
+func (m *HolderDiscrepancy) String() string {
+	return fmt.Sprintf("holder: %v staked balance: %v does not match expected staked: %v", m.TokenHolder, m.StakedBalance, m.ExpectedStaked)
+}
+
+type SolvencyReport struct {
+	Symbols []*SymbolSolvency
+	Holders []*HolderDiscrepancy
+	// Errors holds the rows that could not be reconciled because they have invalid values
+	Errors []string
+}
+
+func (m *SolvencyReport) HasDiscrepancies() bool {
+	if len(m.Holders) > 0 || len(m.Errors) > 0 {
+		return true
+	}
+	for _, symbol := range m.Symbols {
+		if len(symbol.Discrepancies) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *SolvencyReport) String() string {
+	lines := make([]string, 0)
+	for _, symbol := range m.Symbols {
+		lines = append(lines, symbol.String())
+	}
+	for _, holder := range m.Holders {
+		lines = append(lines, holder.String())
+	}
+	for _, err := range m.Errors {
+		lines = append(lines, fmt.Sprintf("error: %v", err))
+	}
+	return strings.Join(lines, "\n")
+}
+
+type tokenKey struct {
+	symbol        string
+	tokenContract eos.AccountName
+}
+
+type holderKey struct {
+	holder eos.AccountName
+	symbol string
+}
+
+type solvencyReconciler struct {
+	report         *SolvencyReport
+	symbols        map[tokenKey]*SymbolSolvency
+	holderStaked   map[holderKey]eos.Asset
+	holderExpected map[holderKey]eos.Asset
+	// stakeContracts maps symbol codes to the token contract of the authorized token
+	stakeContracts map[string]eos.AccountName
+	// holderContracts maps holder balances to their token contract, used when the token is not authorized
+	holderContracts map[holderKey]eos.AccountName
+}
+
+// ReconcileSolvency checks that the balances are consistent with the entries and rounds, and with the actual
+// balances of the bennyfi contract on each token contract, contractBalances is keyed by symbol code and token
+// contract as returned by SolvencyTokenKey. Entry stakes and rex deposits are in the stake token, whose token
+// contract is resolved by symbol from the authorized tokens, or from the balance of the holder if the token is
+// not authorized
+func ReconcileSolvency(balances []Balance, rounds []Round, entries []Entry, tokens []AuthToken, contractBalances map[string]eos.Asset) *SolvencyReport {
+	r := &solvencyReconciler{
+		report:          &SolvencyReport{},
+		symbols:         make(map[tokenKey]*SymbolSolvency),
+		holderStaked:    make(map[holderKey]eos.Asset),
+		holderExpected:  make(map[holderKey]eos.Asset),
+		stakeContracts:  make(map[string]eos.AccountName),
+		holderContracts: make(map[holderKey]eos.AccountName),
+	}
+	for i := range tokens {
+		r.addToken(&tokens[i])
+	}
+	roundsByID := make(map[uint64]*Round, len(rounds))
+	for i := range rounds {
+		roundsByID[rounds[i].RoundID] = &rounds[i]
+	}
+	for i := range balances {
+		r.addBalance(&balances[i])
+	}
+	for i := range entries {
+		r.addEntry(&entries[i], roundsByID[entries[i].RoundID])
+	}
+	for i := range rounds {
+		r.addRound(&rounds[i])
+	}
+	for key, symbol := range r.symbols {
+		r.checkSymbol(symbol, contractBalances[SolvencyTokenKey(key.symbol, key.tokenContract)])
+	}
+	r.checkHolders()
+	sort.Slice(r.report.Symbols, func(i, j int) bool {
+		if r.report.Symbols[i].Symbol.Symbol != r.report.Symbols[j].Symbol.Symbol {
+			return r.report.Symbols[i].Symbol.Symbol < r.report.Symbols[j].Symbol.Symbol
+		}
+		return r.report.Symbols[i].TokenContract < r.report.Symbols[j].TokenContract
+	})
+	return r.report
+}
+
+// SolvencyTokenKey returns the key used to identify a token in the contract balances map
+func SolvencyTokenKey(symbolCode string, tokenContract eos.AccountName) string {
+	return fmt.Sprintf("%v@%v", symbolCode, tokenContract)
+}
+
+func (m *solvencyReconciler) getSymbol(symbol eos.Symbol, tokenContract eos.AccountName) *SymbolSolvency {
+	key := tokenKey{symbol: symbol.Symbol, tokenContract: tokenContract}
+	solvency, ok := m.symbols[key]
+	if !ok {
+		zero := eos.Asset{Amount: 0, Symbol: symbol}
+		solvency = &SymbolSolvency{
+			Symbol:          symbol,
+			TokenContract:   tokenContract,
+			LiquidTotal:     zero,
+			StakedTotal:     zero,
+			LedgerTotal:     zero,
+			ExpectedStaked:  zero,
+			HeldInRex:       zero,
+			ContractBalance: zero,
+		}
+		m.symbols[key] = solvency
+		m.report.Symbols = append(m.report.Symbols, solvency)
+	}
+	return solvency
+}
+
+func (m *solvencyReconciler) addError(format string, args ...interface{}) {
+	m.report.Errors = append(m.report.Errors, fmt.Sprintf(format, args...))
+}
+
+func (m *solvencyReconciler) addToken(token *AuthToken) {
+	symbol, err := eos.StringToSymbol(token.Symbol)
+	if err != nil {
+		m.addError("auth token: %v has invalid symbol, error: %v", token.Symbol, err)
+		return
+	}
+	m.stakeContracts[symbol.Symbol] = token.TokenContract
+}
+
+// stakeContract returns the token contract of the stake token with the specified symbol, holder is used to
+// resolve it from the holder balance when the token is not authorized, it can be empty
+func (m *solvencyReconciler) stakeContract(symbol eos.Symbol, holder eos.AccountName) (eos.AccountName, bool) {
+	if tokenContract, ok := m.stakeContracts[symbol.Symbol]; ok {
+		return tokenContract, true
+	}
+	tokenContract, ok := m.holderContracts[holderKey{holder: holder, symbol: symbol.Symbol}]
+	return tokenContract, ok
+}
+
+func (m *solvencyReconciler) addBalance(balance *Balance) {
+	liquid, err := balance.GetLiquidBalance()
+	if err != nil {
+		m.addError("balance id: %v, %v", balance.ID, err)
+		return
+	}
+	staked, err := balance.GetStakedBalance()
+	if err != nil {
+		m.addError("balance id: %v, %v", balance.ID, err)
+		return
+	}
+	symbol := m.getSymbol(liquid.Symbol, balance.TokenContract)
+	if !addAsset(&symbol.LiquidTotal, liquid) || !addAsset(&symbol.StakedTotal, staked) {
+		m.addError("balance id: %v has a symbol that does not match symbol: %v", balance.ID, symbol.Symbol)
+		return
+	}
+	symbol.LedgerTotal.Amount = symbol.LiquidTotal.Amount + symbol.StakedTotal.Amount
+	key := holderKey{holder: balance.TokenHolder, symbol: liquid.Symbol.Symbol}
+	if _, ok := m.holderContracts[key]; !ok {
+		m.holderContracts[key] = balance.TokenContract
+	}
+	holderStaked := m.holderStaked[key]
+	if holderStaked.Symbol.Symbol == "" {
+		holderStaked = eos.Asset{Amount: 0, Symbol: staked.Symbol}
+	}
+	addAsset(&holderStaked, staked)
+	m.holderStaked[key] = holderStaked
+}
+
+func (m *solvencyReconciler) addExpectedStaked(holder eos.AccountName, tokenContract eos.AccountName, amount eos.Asset) {
+	symbol := m.getSymbol(amount.Symbol, tokenContract)
+	if !addAsset(&symbol.ExpectedStaked, amount) {
+		m.addError("expected staked: %v of holder: %v does not match symbol: %v", amount, holder, symbol.Symbol)
+		return
+	}
+	key := holderKey{holder: holder, symbol: amount.Symbol.Symbol}
+	expected := m.holderExpected[key]
+	if expected.Symbol.Symbol == "" {
+		expected = eos.Asset{Amount: 0, Symbol: amount.Symbol}
+	}
+	addAsset(&expected, amount)
+	m.holderExpected[key] = expected
+}
+
+func (m *solvencyReconciler) addEntry(entry *Entry, round *Round) {
+	if entry.EntryStatus != EntryStaked {
+		return
+	}
+	if round == nil {
+		m.addError("entry id: %v belongs to round: %v which does not exist", entry.EntryID, entry.RoundID)
+		return
+	}
+	stake, err := eos.NewAssetFromString(entry.EntryStake)
+	if err != nil {
+		m.addError("entry id: %v has invalid entry stake: %v, error: %v", entry.EntryID, entry.EntryStake, err)
+		return
+	}
+	tokenContract, ok := m.stakeContract(stake.Symbol, entry.Participant)
+	if !ok {
+		m.addError("entry id: %v stake token: %v is not authorized and participant has no balance", entry.EntryID, stake.Symbol.Symbol)
+		return
+	}
+	m.addExpectedStaked(entry.Participant, tokenContract, stake)
+}
+
+func (m *solvencyReconciler) addRound(round *Round) {
+	if round.RoundType == RoundTypeManagerFunded && containsName(RewardHeldStates, round.CurrentState) {
+		reward, err := eos.NewAssetFromString(round.TotalReward)
+		if err != nil {
+			m.addError("round id: %v has invalid total reward: %v, error: %v", round.RoundID, round.TotalReward, err)
+		} else {
+			m.addExpectedStaked(round.RoundManager, round.RewardTokenContract, reward)
+		}
+	}
+	if round.RoundType == RoundTypeRexPool && containsName(RexHeldStates, round.RexState) {
+		deposits, err := eos.NewAssetFromString(round.TotalDeposits)
+		if err != nil {
+			m.addError("round id: %v has invalid total deposits: %v, error: %v", round.RoundID, round.TotalDeposits, err)
+			return
+		}
+		tokenContract, ok := m.stakeContract(deposits.Symbol, "")
+		if !ok {
+			m.addError("round id: %v stake token: %v of total deposits is not authorized", round.RoundID, deposits.Symbol.Symbol)
+			return
+		}
+		symbol := m.getSymbol(deposits.Symbol, tokenContract)
+		if !addAsset(&symbol.HeldInRex, deposits) {
+			m.addError("round id: %v total deposits: %v do not match symbol: %v", round.RoundID, deposits, symbol.Symbol)
+		}
+	}
+}
+
+func (m *solvencyReconciler) checkSymbol(symbol *SymbolSolvency, contractBalance eos.Asset) {
+	if contractBalance.Symbol.Symbol != "" {
+		symbol.ContractBalance = contractBalance
+	}
+	if symbol.StakedTotal.Amount != symbol.ExpectedStaked.Amount {
+		symbol.Discrepancies = append(symbol.Discrepancies,
+			fmt.Sprintf("staked total: %v does not match expected staked: %v", symbol.StakedTotal, symbol.ExpectedStaked))
+	}
+	held := symbol.ContractBalance.Amount + symbol.HeldInRex.Amount
+	if held != symbol.LedgerTotal.Amount {
+		symbol.Discrepancies = append(symbol.Discrepancies,
+			fmt.Sprintf("contract balance: %v plus held in rex: %v does not match ledger total: %v, difference: %v",
+				symbol.ContractBalance, symbol.HeldInRex, symbol.LedgerTotal, eos.Asset{Amount: held - symbol.LedgerTotal.Amount, Symbol: symbol.Symbol}))
+	}
+}
+
+func (m *solvencyReconciler) checkHolders() {
+	keys := make(map[holderKey]bool)
+	for key := range m.holderStaked {
+		keys[key] = true
+	}
+	for key := range m.holderExpected {
+		keys[key] = true
+	}
+	for key := range keys {
+		staked, hasStaked := m.holderStaked[key]
+		expected, hasExpected := m.holderExpected[key]
+		if !hasStaked {
+			staked = eos.Asset{Amount: 0, Symbol: expected.Symbol}
+		}
+		if !hasExpected {
+			expected = eos.Asset{Amount: 0, Symbol: staked.Symbol}
+		}
+		if staked.Amount != expected.Amount {
+			m.report.Holders = append(m.report.Holders, &HolderDiscrepancy{
+				TokenHolder:    key.holder,
+				Symbol:         staked.Symbol,
+				StakedBalance:  staked,
+				ExpectedStaked: expected,
+			})
+		}
+	}
+	sort.Slice(m.report.Holders, func(i, j int) bool {
+		if m.report.Holders[i].TokenHolder != m.report.Holders[j].TokenHolder {
+			return m.report.Holders[i].TokenHolder < m.report.Holders[j].TokenHolder
+		}
+		return m.report.Holders[i].Symbol.Symbol < m.report.Holders[j].Symbol.Symbol
+	})
+}
+
+// addAsset adds the amount to the total if they have the same symbol
+func addAsset(total *eos.Asset, amount eos.Asset) bool {
+	if !sameSymbol(total.Symbol, amount.Symbol) {
+		return false
+	}
+	total.Amount += amount.Amount
+	return true
+}
+
+func containsName(names []eos.Name, name eos.Name) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckSolvency reads all balances, rounds and entries, and the bennyfi contract balance on each token contract,
+// and reconciles them
+func (m *BennyfiContract) CheckSolvency() (*SolvencyReport, error) {
+	balances, err := m.GetAllBalances()
+	if err != nil {
+		return nil, fmt.Errorf("failed getting balances, error: %v", err)
+	}
+	rounds, err := m.GetAllRounds()
+	if err != nil {
+		return nil, fmt.Errorf("failed getting rounds, error: %v", err)
+	}
+	entries, err := m.GetAllEntries()
+	if err != nil {
+		return nil, fmt.Errorf("failed getting entries, error: %v", err)
+	}
+	authTokens, err := m.GetTokens()
+	if err != nil {
+		return nil, fmt.Errorf("failed getting auth tokens, error: %v", err)
+	}
+	type token struct {
+		symbol        eos.Symbol
+		tokenContract eos.AccountName
+	}
+	tokens := make(map[string]*token)
+	for _, balance := range balances {
+		if liquid, err := balance.GetLiquidBalance(); err == nil {
+			tokens[SolvencyTokenKey(liquid.Symbol.Symbol, balance.TokenContract)] = &token{symbol: liquid.Symbol, tokenContract: balance.TokenContract}
+		}
+	}
+	for _, authToken := range authTokens {
+		if symbol, err := eos.StringToSymbol(authToken.Symbol); err == nil {
+			tokens[SolvencyTokenKey(symbol.Symbol, authToken.TokenContract)] = &token{symbol: symbol, tokenContract: authToken.TokenContract}
+		}
+	}
+	for _, round := range rounds {
+		if reward, err := eos.NewAssetFromString(round.TotalReward); err == nil {
+			tokens[SolvencyTokenKey(reward.Symbol.Symbol, round.RewardTokenContract)] = &token{symbol: reward.Symbol, tokenContract: round.RewardTokenContract}
+		}
+	}
+	contractBalances := make(map[string]eos.Asset)
+	for key, token := range tokens {
+		contractBalance, err := m.EOS.GetBalance(m.ContractName, token.symbol, token.tokenContract)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting contract balance for token: %v, error: %v", key, err)
+		}
+		if contractBalance == nil {
+			contractBalance = &eos.Asset{Amount: 0, Symbol: token.symbol}
+		}
+		contractBalances[key] = *contractBalance
+	}
+	return ReconcileSolvency(balances, rounds, entries, authTokens, contractBalances), nil
+}
@@ -0,0 +1,83 @@
+package bennyfi_test
+
+import (
+	"testing"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+	"gotest.tools/assert"
+)
+
+func TestReconcileSolvency(t *testing.T) {
+	token := eos.AN("eosio.token")
+	balances := []bennyfi.Balance{
+		{ID: 0, TokenHolder: "alice", Symbol: tlos.String(), LiquidBalance: "5.0000 TLOS", StakedBalance: "10.0000 TLOS", TokenContract: token},
+		{ID: 1, TokenHolder: "bob", Symbol: tlos.String(), LiquidBalance: "0.0000 TLOS", StakedBalance: "10.0000 TLOS", TokenContract: token},
+		{ID: 2, TokenHolder: "manager", Symbol: tlos.String(), LiquidBalance: "1.0000 TLOS", StakedBalance: "100.0000 TLOS", TokenContract: token},
+	}
+	rounds := []bennyfi.Round{
+		{RoundID: 1, RoundType: bennyfi.RoundTypeManagerFunded, CurrentState: bennyfi.RoundOpen, EntryStake: "10.0000 TLOS",
+			TotalReward: "100.0000 TLOS", RewardTokenContract: token, RoundManager: "manager"},
+	}
+	entries := []bennyfi.Entry{
+		{EntryID: 1, RoundID: 1, Participant: "alice", EntryStake: "10.0000 TLOS", EntryStatus: bennyfi.EntryStaked},
+		{EntryID: 2, RoundID: 1, Participant: "bob", EntryStake: "10.0000 TLOS", EntryStatus: bennyfi.EntryStaked},
+		{EntryID: 3, RoundID: 1, Participant: "carol", EntryStake: "10.0000 TLOS", EntryStatus: bennyfi.EntryUnstaked},
+	}
+	tokens := []bennyfi.AuthToken{{Symbol: tlos.String(), TokenContract: token}}
+	contractBalances := map[string]eos.Asset{
+		bennyfi.SolvencyTokenKey("TLOS", token): {Amount: 1260000, Symbol: tlos},
+	}
+	report := bennyfi.ReconcileSolvency(balances, rounds, entries, tokens, contractBalances)
+	assert.Assert(t, !report.HasDiscrepancies(), report.String())
+	assert.Equal(t, report.Symbols[0].LedgerTotal.String(), "126.0000 TLOS")
+
+	balances[1].StakedBalance = "8.0000 TLOS"
+	contractBalances[bennyfi.SolvencyTokenKey("TLOS", token)] = eos.Asset{Amount: 1200000, Symbol: tlos}
+	report = bennyfi.ReconcileSolvency(balances, rounds, entries, tokens, contractBalances)
+	assert.Assert(t, report.HasDiscrepancies())
+	assert.Equal(t, len(report.Symbols[0].Discrepancies), 2, report.String())
+	assert.Equal(t, len(report.Holders), 1)
+	assert.Equal(t, report.Holders[0].TokenHolder, eos.AN("bob"))
+	assert.Equal(t, report.Holders[0].ExpectedStaked.String(), "10.0000 TLOS")
+}
+
+func TestReconcileSolvencyStakeAndRewardContracts(t *testing.T) {
+	stakeContract := eos.AN("eosio.token")
+	rewardContract := eos.AN("rewardtoken")
+	beny, _ := eos.StringToSymbol("4,BENY")
+	balances := []bennyfi.Balance{
+		{ID: 0, TokenHolder: "alice", Symbol: tlos.String(), LiquidBalance: "0.0000 TLOS", StakedBalance: "10.0000 TLOS", TokenContract: stakeContract},
+		{ID: 1, TokenHolder: "bob", Symbol: tlos.String(), LiquidBalance: "0.0000 TLOS", StakedBalance: "10.0000 TLOS", TokenContract: stakeContract},
+		{ID: 2, TokenHolder: "carol", Symbol: tlos.String(), LiquidBalance: "0.0000 TLOS", StakedBalance: "10.0000 TLOS", TokenContract: stakeContract},
+		{ID: 3, TokenHolder: "manager", Symbol: beny.String(), LiquidBalance: "0.0000 BENY", StakedBalance: "50.0000 BENY", TokenContract: rewardContract},
+	}
+	rounds := []bennyfi.Round{
+		{RoundID: 1, RoundType: bennyfi.RoundTypeManagerFunded, CurrentState: bennyfi.RoundOpen, EntryStake: "10.0000 TLOS",
+			TotalReward: "50.0000 BENY", RewardTokenContract: rewardContract, RoundManager: "manager"},
+		{RoundID: 2, RoundType: bennyfi.RoundTypeRexPool, CurrentState: bennyfi.RoundOpen, RexState: bennyfi.RexStateInSavings,
+			EntryStake: "10.0000 TLOS", TotalDeposits: "10.0000 TLOS", RewardTokenContract: rewardContract, RoundManager: "manager"},
+	}
+	entries := []bennyfi.Entry{
+		{EntryID: 1, RoundID: 1, Participant: "alice", EntryStake: "10.0000 TLOS", EntryStatus: bennyfi.EntryStaked},
+		{EntryID: 2, RoundID: 1, Participant: "bob", EntryStake: "10.0000 TLOS", EntryStatus: bennyfi.EntryStaked},
+		{EntryID: 3, RoundID: 2, Participant: "carol", EntryStake: "10.0000 TLOS", EntryStatus: bennyfi.EntryStaked},
+	}
+	tokens := []bennyfi.AuthToken{{Symbol: tlos.String(), TokenContract: stakeContract}}
+	contractBalances := map[string]eos.Asset{
+		bennyfi.SolvencyTokenKey("TLOS", stakeContract):  {Amount: 200000, Symbol: tlos},
+		bennyfi.SolvencyTokenKey("BENY", rewardContract): {Amount: 500000, Symbol: beny},
+	}
+	report := bennyfi.ReconcileSolvency(balances, rounds, entries, tokens, contractBalances)
+	assert.Assert(t, !report.HasDiscrepancies(), report.String())
+	assert.Equal(t, len(report.Symbols), 2, report.String())
+	assert.Equal(t, report.Symbols[0].TokenContract, rewardContract)
+	assert.Equal(t, report.Symbols[0].ExpectedStaked.String(), "50.0000 BENY")
+	assert.Equal(t, report.Symbols[1].TokenContract, stakeContract)
+	assert.Equal(t, report.Symbols[1].ExpectedStaked.String(), "30.0000 TLOS")
+	assert.Equal(t, report.Symbols[1].HeldInRex.String(), "10.0000 TLOS")
+
+	report = bennyfi.ReconcileSolvency(balances, rounds, entries, nil, contractBalances)
+	assert.Equal(t, len(report.Errors), 1, report.String())
+	assert.Equal(t, len(report.Symbols), 2, report.String())
+}
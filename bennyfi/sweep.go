@@ -0,0 +1,340 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package bennyfi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"gopkg.in/yaml.v2"
+)
+
+// SweepRule withdraws the liquid balance of the account when it goes over the threshold, keep is the amount left
+// in the bank balance after the sweep, if not specified the whole liquid balance is withdrawn
+type SweepRule struct {
+	Account   eos.AccountName `yaml:"account" json:"account"`
+	Threshold string          `yaml:"threshold" json:"threshold"`
+	Keep      string          `yaml:"keep,omitempty" json:"keep,omitempty"`
+}
+
+func (m *SweepRule) GetThreshold() (eos.Asset, error) {
+	threshold, err := eos.NewAssetFromString(m.Threshold)
+	if err != nil {
+		return eos.Asset{}, fmt.Errorf("invalid threshold: %v for account: %v, error: %v", m.Threshold, m.Account, err)
+	}
+	return threshold, nil
+}
+
+func (m *SweepRule) GetKeep() (eos.Asset, error) {
+	threshold, err := m.GetThreshold()
+	if err != nil {
+		return eos.Asset{}, err
+	}
+	if m.Keep == "" {
+		return eos.Asset{Amount: 0, Symbol: threshold.Symbol}, nil
+	}
+	keep, err := eos.NewFixedSymbolAssetFromString(threshold.Symbol, m.Keep)
+	if err != nil {
+		return eos.Asset{}, fmt.Errorf("invalid keep: %v for account: %v, error: %v", m.Keep, m.Account, err)
+	}
+	return keep, nil
+}
+
+func (m *SweepRule) Validate() error {
+	threshold, err := m.GetThreshold()
+	if err != nil {
+		return err
+	}
+	keep, err := m.GetKeep()
+	if err != nil {
+		return err
+	}
+	if threshold.Amount < 0 || keep.Amount < 0 {
+		return fmt.Errorf("threshold and keep for account: %v must not be negative", m.Account)
+	}
+	if keep.Amount > threshold.Amount {
+		return fmt.Errorf("keep: %v is greater than threshold: %v for account: %v", keep, threshold, m.Account)
+	}
+	return nil
+}
+
+type SweepConfig struct {
+	Rules []*SweepRule `yaml:"rules" json:"rules"`
+}
+
+func LoadSweepConfig(file string) (*SweepConfig, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading sweep config file: %v, error: %v", file, err)
+	}
+	config := &SweepConfig{}
+	err = yaml.UnmarshalStrict(data, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing sweep config file: %v, error: %v", file, err)
+	}
+	for _, rule := range config.Rules {
+		err = rule.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// SweepRecord is written to the sweep log for every sweep, Action is withdraw or withdrawtot. Quantity is the
+// planned amount, Withdrawn is the decrease of the liquid balance observed after the sweep executed, they can
+// differ for withdrawtot if the balance changed in between
+type SweepRecord struct {
+	Time      time.Time       `json:"time"`
+	Account   eos.AccountName `json:"account"`
+	Liquid    string          `json:"liquid"`
+	Threshold string          `json:"threshold"`
+	Quantity  string          `json:"quantity"`
+	Action    eos.ActionName  `json:"action"`
+	DryRun    bool            `json:"dry_run"`
+	Withdrawn string          `json:"withdrawn,omitempty"`
+	TxID      string          `json:"tx_id,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// PlanSweep returns the sweep required by the rule for the balance, nil if the liquid balance is not over the
+// threshold
+func PlanSweep(rule *SweepRule, balance *Balance, now time.Time) (*SweepRecord, error) {
+	if balance == nil {
+		return nil, nil
+	}
+	threshold, err := rule.GetThreshold()
+	if err != nil {
+		return nil, err
+	}
+	keep, err := rule.GetKeep()
+	if err != nil {
+		return nil, err
+	}
+	liquid, err := balance.GetLiquidBalance()
+	if err != nil {
+		return nil, err
+	}
+	if !sameSymbol(liquid.Symbol, threshold.Symbol) {
+		return nil, fmt.Errorf("liquid balance: %v does not match threshold symbol: %v", liquid, threshold.Symbol)
+	}
+	if liquid.Amount <= threshold.Amount {
+		return nil, nil
+	}
+	record := &SweepRecord{
+		Time:      now,
+		Account:   rule.Account,
+		Liquid:    liquid.String(),
+		Threshold: threshold.String(),
+		Quantity:  eos.Asset{Amount: liquid.Amount - keep.Amount, Symbol: liquid.Symbol}.String(),
+		Action:    "withdraw",
+	}
+	if keep.Amount == 0 {
+		record.Action = "withdrawtot"
+	}
+	return record, nil
+}
+
+type SweepClient interface {
+	GetBalance(tokenHolder eos.AccountName, symbol string) (*Balance, error)
+	WithdrawTx(from eos.AccountName, quantity eos.Asset) (*eos.PushTransactionFullResp, error)
+	WithdrawTotTx(from eos.AccountName, symbol eos.Symbol) (*eos.PushTransactionFullResp, error)
+}
+
+// SweepAgent checks the rules and sweeps the balances over their threshold, every sweep is appended as a JSON
+// line to the log file. In dry run mode sweeps are only logged.
+type SweepAgent struct {
+	Client  SweepClient
+	Rules   []*SweepRule
+	LogFile string
+	DryRun  bool
+	// OnError is called when a rule can not be processed, by default the error is logged
+	OnError func(error)
+}
+
+func NewSweepAgent(client SweepClient, config *SweepConfig, logFile string, dryRun bool) *SweepAgent {
+	return &SweepAgent{
+		Client:  client,
+		Rules:   config.Rules,
+		LogFile: logFile,
+		DryRun:  dryRun,
+		OnError: func(err error) {
+			log.Println("Sweep failed, error: ", err)
+		},
+	}
+}
+
+// RunOnce processes all the rules, returns the sweeps performed, failed sweeps are returned and logged with
+// their error. Invalid rules are reported through OnError and skipped. A log failure is reported through OnError
+// if the sweep was executed, since aborting would hide a withdrawal that already happened, otherwise it is returned
+func (m *SweepAgent) RunOnce() ([]*SweepRecord, error) {
+	records := make([]*SweepRecord, 0)
+	for _, rule := range m.Rules {
+		err := rule.Validate()
+		if err != nil {
+			m.OnError(err)
+			continue
+		}
+		record, err := m.sweep(rule)
+		if err != nil {
+			m.OnError(err)
+		}
+		if record == nil {
+			continue
+		}
+		records = append(records, record)
+		err = m.log(record)
+		if err != nil {
+			if record.TxID == "" {
+				return records, err
+			}
+			m.OnError(fmt.Errorf("swept: %v from: %v, Tx ID: %v, but %v", record.Quantity, rule.Account, record.TxID, err))
+		}
+	}
+	return records, nil
+}
+
+func (m *SweepAgent) sweep(rule *SweepRule) (*SweepRecord, error) {
+	threshold, err := rule.GetThreshold()
+	if err != nil {
+		return nil, err
+	}
+	balance, err := m.Client.GetBalance(rule.Account, threshold.Symbol.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed getting balance of: %v, error: %v", rule.Account, err)
+	}
+	record, err := PlanSweep(rule, balance, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed planning sweep for: %v, error: %v", rule.Account, err)
+	}
+	if record == nil {
+		return nil, nil
+	}
+	record.DryRun = m.DryRun
+	if m.DryRun {
+		return record, nil
+	}
+	var resp *eos.PushTransactionFullResp
+	if record.Action == "withdrawtot" {
+		resp, err = m.Client.WithdrawTotTx(rule.Account, threshold.Symbol)
+	} else {
+		var quantity eos.Asset
+		quantity, err = eos.NewAssetFromString(record.Quantity)
+		if err != nil {
+			record.Error = err.Error()
+			return record, fmt.Errorf("invalid sweep quantity: %v for: %v, error: %v", record.Quantity, rule.Account, err)
+		}
+		resp, err = m.Client.WithdrawTx(rule.Account, quantity)
+	}
+	if err != nil {
+		record.Error = err.Error()
+		return record, fmt.Errorf("failed sweeping: %v from: %v, error: %v", record.Quantity, rule.Account, err)
+	}
+	record.TxID = resp.TransactionID
+	withdrawn, err := m.withdrawn(rule.Account, balance)
+	if err != nil {
+		record.Error = err.Error()
+		return record, fmt.Errorf("swept: %v from: %v, Tx ID: %v, but %v", record.Quantity, rule.Account, record.TxID, err)
+	}
+	record.Withdrawn = withdrawn.String()
+	return record, nil
+}
+
+// withdrawn returns the decrease of the liquid balance since before
+func (m *SweepAgent) withdrawn(account eos.AccountName, before *Balance) (eos.Asset, error) {
+	beforeLiquid, err := before.GetLiquidBalance()
+	if err != nil {
+		return eos.Asset{}, err
+	}
+	after, err := m.Client.GetBalance(account, beforeLiquid.Symbol.String())
+	if err != nil {
+		return eos.Asset{}, fmt.Errorf("failed getting balance after sweep, error: %v", err)
+	}
+	afterLiquid := eos.Asset{Amount: 0, Symbol: beforeLiquid.Symbol}
+	if after != nil {
+		afterLiquid, err = after.GetLiquidBalance()
+		if err != nil {
+			return eos.Asset{}, err
+		}
+	}
+	return eos.Asset{Amount: beforeLiquid.Amount - afterLiquid.Amount, Symbol: beforeLiquid.Symbol}, nil
+}
+
+func (m *SweepAgent) log(record *SweepRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed serializing sweep record, error: %v", err)
+	}
+	file, err := os.OpenFile(m.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed opening sweep log: %v, error: %v", m.LogFile, err)
+	}
+	defer file.Close()
+	_, err = file.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed writing sweep log: %v, error: %v", m.LogFile, err)
+	}
+	return nil
+}
+
+// Run processes the rules at the specified interval until the context is cancelled
+func (m *SweepAgent) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		_, err := m.RunOnce()
+		if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// LoadSweepLog reads the sweep records from the log file
+func LoadSweepLog(file string) ([]*SweepRecord, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading sweep log: %v, error: %v", file, err)
+	}
+	records := make([]*SweepRecord, 0)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for decoder.More() {
+		record := &SweepRecord{}
+		err = decoder.Decode(record)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing sweep log: %v, error: %v", file, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
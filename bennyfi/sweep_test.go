@@ -0,0 +1,114 @@
+package bennyfi_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+	"gotest.tools/assert"
+)
+
+type sweepClientMock struct {
+	balances  map[eos.AccountName]*bennyfi.Balance
+	withdrawn []string
+}
+
+func (m *sweepClientMock) GetBalance(tokenHolder eos.AccountName, symbol string) (*bennyfi.Balance, error) {
+	return m.balances[tokenHolder], nil
+}
+
+func (m *sweepClientMock) WithdrawTx(from eos.AccountName, quantity eos.Asset) (*eos.PushTransactionFullResp, error) {
+	m.withdrawn = append(m.withdrawn, quantity.String())
+	m.debit(from, quantity.Amount)
+	return &eos.PushTransactionFullResp{TransactionID: "1"}, nil
+}
+
+func (m *sweepClientMock) WithdrawTotTx(from eos.AccountName, symbol eos.Symbol) (*eos.PushTransactionFullResp, error) {
+	m.withdrawn = append(m.withdrawn, "all")
+	liquid, _ := m.balances[from].GetLiquidBalance()
+	m.debit(from, liquid.Amount)
+	return &eos.PushTransactionFullResp{TransactionID: "2"}, nil
+}
+
+func (m *sweepClientMock) debit(from eos.AccountName, amount eos.Int64) {
+	liquid, _ := m.balances[from].GetLiquidBalance()
+	m.balances[from] = &bennyfi.Balance{LiquidBalance: eos.Asset{Amount: liquid.Amount - amount, Symbol: liquid.Symbol}.String()}
+}
+
+func TestSweepAgent(t *testing.T) {
+	client := &sweepClientMock{
+		balances: map[eos.AccountName]*bennyfi.Balance{
+			"beneficiary": {LiquidBalance: "150.0000 TLOS"},
+			"manager":     {LiquidBalance: "50.0000 TLOS"},
+			"player":      {LiquidBalance: "20.0000 TLOS"},
+		},
+	}
+	config := &bennyfi.SweepConfig{
+		Rules: []*bennyfi.SweepRule{
+			{Account: "beneficiary", Threshold: "100.0000 TLOS", Keep: "10"},
+			{Account: "manager", Threshold: "10.0000 TLOS"},
+			{Account: "player", Threshold: "100.0000 TLOS"},
+		},
+	}
+	logFile := filepath.Join(t.TempDir(), "sweeps.log")
+
+	agent := bennyfi.NewSweepAgent(client, config, logFile, true)
+	records, err := agent.RunOnce()
+	assert.NilError(t, err)
+	assert.Equal(t, len(records), 2)
+	assert.Equal(t, len(client.withdrawn), 0)
+
+	agent.DryRun = false
+	records, err = agent.RunOnce()
+	assert.NilError(t, err)
+	assert.Equal(t, len(records), 2)
+	assert.DeepEqual(t, client.withdrawn, []string{"140.0000 TLOS", "all"})
+
+	logged, err := bennyfi.LoadSweepLog(logFile)
+	assert.NilError(t, err)
+	assert.Equal(t, len(logged), 4)
+	assert.Assert(t, logged[0].DryRun)
+	assert.Equal(t, logged[2].Action, eos.ActN("withdraw"))
+	assert.Equal(t, logged[2].TxID, "1")
+	assert.Equal(t, logged[2].Withdrawn, "140.0000 TLOS")
+	assert.Equal(t, logged[3].Action, eos.ActN("withdrawtot"))
+	assert.Equal(t, logged[3].TxID, "2")
+	assert.Equal(t, logged[3].Withdrawn, "50.0000 TLOS")
+
+	assert.ErrorContains(t, (&bennyfi.SweepRule{Account: "a", Threshold: "1.0000 TLOS", Keep: "2"}).Validate(), "greater than threshold")
+}
+
+func TestSweepAgentErrors(t *testing.T) {
+	client := &sweepClientMock{
+		balances: map[eos.AccountName]*bennyfi.Balance{
+			"beneficiary": {LiquidBalance: "150.0000 TLOS"},
+			"manager":     {LiquidBalance: "50.0000 TLOS"},
+		},
+	}
+	config := &bennyfi.SweepConfig{
+		Rules: []*bennyfi.SweepRule{
+			{Account: "beneficiary", Threshold: "100.0000"},
+			{Account: "manager", Threshold: "10.0000 TLOS", Keep: "5"},
+		},
+	}
+	// the log file is a directory so that writing to it fails
+	logFile := t.TempDir()
+	errs := make([]error, 0)
+	agent := bennyfi.NewSweepAgent(client, config, logFile, false)
+	agent.OnError = func(err error) {
+		errs = append(errs, err)
+	}
+	records, err := agent.RunOnce()
+	assert.NilError(t, err)
+	assert.Equal(t, len(records), 1)
+	assert.DeepEqual(t, client.withdrawn, []string{"45.0000 TLOS"})
+	assert.Equal(t, len(errs), 2)
+	assert.ErrorContains(t, errs[0], "invalid threshold")
+	assert.ErrorContains(t, errs[1], "Tx ID: 1")
+
+	agent.DryRun = true
+	client.balances["manager"] = &bennyfi.Balance{LiquidBalance: "50.0000 TLOS"}
+	_, err = agent.RunOnce()
+	assert.ErrorContains(t, err, "sweep log")
+}
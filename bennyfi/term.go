@@ -23,6 +23,7 @@ package bennyfi
 
 import (
 	"fmt"
+	"time"
 
 	eos "github.com/eoscanada/eos-go"
 )
@@ -67,6 +68,14 @@ func (m *BennyfiContract) NewTerm(term *Term) (string, error) {
 }
 
 func (m *BennyfiContract) NewTermFromTermArgs(termArgs *NewTermArgs) (string, error) {
+	return m.Exec(m.NewTermAction(termArgs))
+}
+
+func (m *BennyfiContract) ProposeNewTerm(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, termArgs *NewTermArgs) (string, error) {
+	return m.Propose(proposerName, requested, expireIn, m.NewTermAction(termArgs))
+}
+
+func (m *BennyfiContract) NewTermAction(termArgs *NewTermArgs) *ContractAction {
 	actionData := make(map[string]interface{})
 	actionData["round_manager"] = termArgs.RoundManager
 	actionData["term_name"] = termArgs.TermName
@@ -75,8 +84,7 @@ func (m *BennyfiContract) NewTermFromTermArgs(termArgs *NewTermArgs) (string, er
 	actionData["round_type"] = termArgs.RoundType
 	actionData["round_access"] = termArgs.RoundAccess
 	actionData["beneficiary_perc_x100000"] = termArgs.BeneficiaryPerc
-
-	return m.ExecAction(termArgs.RoundManager, "newterm", actionData)
+	return NewContractAction(termArgs.RoundManager, "newterm", actionData)
 }
 
 func (m *BennyfiContract) GetTerms() ([]Term, error) {
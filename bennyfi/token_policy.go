@@ -0,0 +1,383 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package bennyfi
+
+import (
+	"fmt"
+	"strings"
+
+	eos "github.com/eoscanada/eos-go"
+)
+
+var (
+	TokenRoleEntryStake  = eos.Name("entrystake")
+	TokenRoleTotalReward = eos.Name("totalreward")
+)
+
+// TokenViolation describes a single way in which an amount or a token configuration
+// breaks the limits defined in the authtokens table
+type TokenViolation struct {
+	Role   eos.Name
+	Symbol string
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (m *TokenViolation) Error() string {
+	msg := fmt.Sprintf("%v: %v", m.Field, m.Reason)
+	if m.Value != "" {
+		msg = fmt.Sprintf("%v, value: %v", msg, m.Value)
+	}
+	if m.Role != "" {
+		msg = fmt.Sprintf("%v, role: %v", msg, m.Role)
+	}
+	if m.Symbol != "" {
+		msg = fmt.Sprintf("%v, symbol: %v", msg, m.Symbol)
+	}
+	return msg
+}
+
+// TokenViolations is returned as error by the TokenPolicy validations when at least one violation is found
+type TokenViolations []*TokenViolation
+
+func (m TokenViolations) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, violation := range m {
+		msgs = append(msgs, violation.Error())
+	}
+	return fmt.Sprintf("token policy violations: [%v]", strings.Join(msgs, "; "))
+}
+
+func (m TokenViolations) toError() error {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+func (m *TokenLimits) Min() (eos.Asset, error) {
+	return eos.NewAssetFromString(m.MinValue)
+}
+
+func (m *TokenLimits) Max() (eos.Asset, error) {
+	return eos.NewAssetFromString(m.MaxValue)
+}
+
+func (m *AuthToken) GetSymbol() (eos.Symbol, error) {
+	return eos.StringToSymbol(m.Symbol)
+}
+
+func (m *AuthToken) GetTokenRole(role eos.Name) *TokenRole {
+	for _, tokenRole := range m.TokenRoles {
+		if tokenRole.Key == role {
+			return tokenRole
+		}
+	}
+	return nil
+}
+
+// TokenPolicy validates amounts against the token roles defined in the authtokens table,
+// so that invalid values are caught before any transaction is built
+type TokenPolicy struct {
+	Tokens map[string]*AuthToken
+}
+
+func NewTokenPolicy(authTokens []AuthToken) *TokenPolicy {
+	tokens := make(map[string]*AuthToken, len(authTokens))
+	for i := range authTokens {
+		tokens[symbolCode(authTokens[i].Symbol)] = &authTokens[i]
+	}
+	return &TokenPolicy{
+		Tokens: tokens,
+	}
+}
+
+func (m *BennyfiContract) GetTokenPolicy() (*TokenPolicy, error) {
+	authTokens, err := m.GetTokens()
+	if err != nil {
+		return nil, fmt.Errorf("failed loading tokens for token policy, error: %v", err)
+	}
+	return NewTokenPolicy(authTokens), nil
+}
+
+func (m *TokenPolicy) GetToken(symbol eos.Symbol) *AuthToken {
+	return m.Tokens[symbol.Symbol]
+}
+
+// ValidateAmount checks that the amount symbol is an accepted token and that the amount is within the
+// limits of the specified role
+func (m *TokenPolicy) ValidateAmount(role eos.Name, field string, amount eos.Asset) error {
+	return m.validateAmount(role, field, amount).toError()
+}
+
+func (m *TokenPolicy) validateAmount(role eos.Name, field string, amount eos.Asset) TokenViolations {
+	violations, authToken := m.validateSymbol(role, field, amount)
+	if authToken == nil {
+		return violations
+	}
+	tokenRole := authToken.GetTokenRole(role)
+	if tokenRole == nil || tokenRole.Value == nil {
+		return append(violations, &TokenViolation{
+			Role:   role,
+			Symbol: authToken.Symbol,
+			Field:  field,
+			Value:  amount.String(),
+			Reason: "token role is not defined for token",
+		})
+	}
+	min, err := tokenRole.Value.Min()
+	if err != nil {
+		return append(violations, m.invalidLimitViolation(role, authToken.Symbol, "min_value", tokenRole.Value.MinValue, err))
+	}
+	max, err := tokenRole.Value.Max()
+	if err != nil {
+		return append(violations, m.invalidLimitViolation(role, authToken.Symbol, "max_value", tokenRole.Value.MaxValue, err))
+	}
+	if amount.Amount < min.Amount {
+		violations = append(violations, &TokenViolation{
+			Role:   role,
+			Symbol: authToken.Symbol,
+			Field:  field,
+			Value:  amount.String(),
+			Reason: fmt.Sprintf("amount is less than the min value: %v", min),
+		})
+	}
+	if amount.Amount > max.Amount {
+		violations = append(violations, &TokenViolation{
+			Role:   role,
+			Symbol: authToken.Symbol,
+			Field:  field,
+			Value:  amount.String(),
+			Reason: fmt.Sprintf("amount is greater than the max value: %v", max),
+		})
+	}
+	return violations
+}
+
+func (m *TokenPolicy) validateSymbol(role eos.Name, field string, amount eos.Asset) (TokenViolations, *AuthToken) {
+	authToken := m.GetToken(amount.Symbol)
+	if authToken == nil {
+		return TokenViolations{
+			&TokenViolation{
+				Role:   role,
+				Symbol: amount.Symbol.String(),
+				Field:  field,
+				Value:  amount.String(),
+				Reason: "token is not authorized",
+			},
+		}, nil
+	}
+	symbol, err := authToken.GetSymbol()
+	if err != nil {
+		return TokenViolations{
+			m.invalidLimitViolation(role, authToken.Symbol, "symbol", authToken.Symbol, err),
+		}, nil
+	}
+	if symbol.Precision != amount.Symbol.Precision {
+		return TokenViolations{
+			&TokenViolation{
+				Role:   role,
+				Symbol: authToken.Symbol,
+				Field:  field,
+				Value:  amount.String(),
+				Reason: fmt.Sprintf("precision mismatch, expected: %v, found: %v", symbol.Precision, amount.Symbol.Precision),
+			},
+		}, nil
+	}
+	return nil, authToken
+}
+
+func (m *TokenPolicy) invalidLimitViolation(role eos.Name, symbol, field, value string, err error) *TokenViolation {
+	return &TokenViolation{
+		Role:   role,
+		Symbol: symbol,
+		Field:  field,
+		Value:  value,
+		Reason: fmt.Sprintf("invalid value stored in authtokens table, error: %v", err),
+	}
+}
+
+func (m *TokenPolicy) ValidateNewRoundArgs(args *NewRoundArgs) error {
+	violations := make(TokenViolations, 0)
+	entryStake, err := eos.NewAssetFromString(args.EntryStake)
+	if err != nil {
+		violations = append(violations, &TokenViolation{
+			Role:   TokenRoleEntryStake,
+			Field:  "entry_stake",
+			Value:  args.EntryStake,
+			Reason: fmt.Sprintf("invalid asset, error: %v", err),
+		})
+	} else {
+		violations = append(violations, m.validateAmount(TokenRoleEntryStake, "entry_stake", entryStake)...)
+	}
+	totalReward, err := eos.NewAssetFromString(args.TotalReward)
+	if err != nil {
+		violations = append(violations, &TokenViolation{
+			Role:   TokenRoleTotalReward,
+			Field:  "total_reward",
+			Value:  args.TotalReward,
+			Reason: fmt.Sprintf("invalid asset, error: %v", err),
+		})
+	} else {
+		violations = append(violations, m.validateAmount(TokenRoleTotalReward, "total_reward", totalReward)...)
+	}
+	return violations.toError()
+}
+
+// ValidateWithdraw checks that the quantity is positive and that its symbol is an accepted token
+func (m *TokenPolicy) ValidateWithdraw(quantity eos.Asset) error {
+	violations, _ := m.validateSymbol("", "quantity", quantity)
+	if quantity.Amount <= 0 {
+		violations = append(violations, &TokenViolation{
+			Symbol: quantity.Symbol.String(),
+			Field:  "quantity",
+			Value:  quantity.String(),
+			Reason: "quantity must be positive",
+		})
+	}
+	return violations.toError()
+}
+
+// ValidateDeposit checks that the quantity is positive and an accepted token, same rules as withdraw
+func (m *TokenPolicy) ValidateDeposit(quantity eos.Asset) error {
+	return m.ValidateWithdraw(quantity)
+}
+
+// ValidateTokenRole checks that min <= max, that min and max have the same symbol, and that they are
+// consistent with the token if it is already registered
+func (m *TokenPolicy) ValidateTokenRole(args *SetTokenRoleArgs) error {
+	violations := validateLimits(args.TokenRole, args.MinValue, args.MaxValue)
+	if authToken := m.GetToken(args.MinValue.Symbol); authToken != nil {
+		if authToken.TokenContract != args.TokenContract {
+			violations = append(violations, &TokenViolation{
+				Role:   args.TokenRole,
+				Symbol: authToken.Symbol,
+				Field:  "token_contract",
+				Value:  string(args.TokenContract),
+				Reason: fmt.Sprintf("token contract mismatch, expected: %v", authToken.TokenContract),
+			})
+		}
+		if authToken.Symbol != args.MinValue.Symbol.String() {
+			violations = append(violations, &TokenViolation{
+				Role:   args.TokenRole,
+				Symbol: authToken.Symbol,
+				Field:  "symbol",
+				Value:  args.MinValue.Symbol.String(),
+				Reason: fmt.Sprintf("symbol mismatch, expected: %v", authToken.Symbol),
+			})
+		}
+	}
+	return violations.toError()
+}
+
+// ValidateAuthToken checks that all the token roles have valid limits with the token symbol
+func ValidateAuthToken(authToken *AuthToken) error {
+	violations := make(TokenViolations, 0)
+	symbol, err := authToken.GetSymbol()
+	if err != nil {
+		return append(violations, &TokenViolation{
+			Symbol: authToken.Symbol,
+			Field:  "symbol",
+			Value:  authToken.Symbol,
+			Reason: fmt.Sprintf("invalid symbol, error: %v", err),
+		})
+	}
+	for _, tokenRole := range authToken.TokenRoles {
+		if tokenRole.Value == nil {
+			violations = append(violations, &TokenViolation{
+				Role:   tokenRole.Key,
+				Symbol: authToken.Symbol,
+				Field:  "value",
+				Reason: "token limits not specified",
+			})
+			continue
+		}
+		min, err := tokenRole.Value.Min()
+		if err != nil {
+			violations = append(violations, &TokenViolation{
+				Role:   tokenRole.Key,
+				Symbol: authToken.Symbol,
+				Field:  "min_value",
+				Value:  tokenRole.Value.MinValue,
+				Reason: fmt.Sprintf("invalid asset, error: %v", err),
+			})
+			continue
+		}
+		max, err := tokenRole.Value.Max()
+		if err != nil {
+			violations = append(violations, &TokenViolation{
+				Role:   tokenRole.Key,
+				Symbol: authToken.Symbol,
+				Field:  "max_value",
+				Value:  tokenRole.Value.MaxValue,
+				Reason: fmt.Sprintf("invalid asset, error: %v", err),
+			})
+			continue
+		}
+		roleViolations := validateLimits(tokenRole.Key, min, max)
+		if len(roleViolations) == 0 && !sameSymbol(min.Symbol, symbol) {
+			roleViolations = append(roleViolations, &TokenViolation{
+				Role:   tokenRole.Key,
+				Symbol: authToken.Symbol,
+				Field:  "min_value",
+				Value:  min.String(),
+				Reason: fmt.Sprintf("symbol mismatch, expected: %v", authToken.Symbol),
+			})
+		}
+		violations = append(violations, roleViolations...)
+	}
+	return violations.toError()
+}
+
+func validateLimits(role eos.Name, min, max eos.Asset) TokenViolations {
+	violations := make(TokenViolations, 0)
+	if !sameSymbol(min.Symbol, max.Symbol) {
+		violations = append(violations, &TokenViolation{
+			Role:   role,
+			Symbol: min.Symbol.String(),
+			Field:  "max_value",
+			Value:  max.String(),
+			Reason: fmt.Sprintf("symbol mismatch, min value symbol: %v, max value symbol: %v", min.Symbol, max.Symbol),
+		})
+		return violations
+	}
+	if min.Amount > max.Amount {
+		violations = append(violations, &TokenViolation{
+			Role:   role,
+			Symbol: min.Symbol.String(),
+			Field:  "min_value",
+			Value:  min.String(),
+			Reason: fmt.Sprintf("min value is greater than max value: %v", max),
+		})
+	}
+	return violations
+}
+
+func sameSymbol(s1, s2 eos.Symbol) bool {
+	return s1.Symbol == s2.Symbol && s1.Precision == s2.Precision
+}
+
+func symbolCode(symbol string) string {
+	parts := strings.Split(symbol, ",")
+	return parts[len(parts)-1]
+}
@@ -0,0 +1,93 @@
+package bennyfi_test
+
+import (
+	"testing"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+	"gotest.tools/assert"
+)
+
+var tlos = eos.Symbol{Precision: 4, Symbol: "TLOS"}
+
+func newTestTokenPolicy() *bennyfi.TokenPolicy {
+	return bennyfi.NewTokenPolicy([]bennyfi.AuthToken{
+		{
+			Authorizer:    eos.AN("admin"),
+			Symbol:        tlos.String(),
+			TokenContract: eos.AN("eosio.token"),
+			TokenRoles: bennyfi.TokenRoles{
+				bennyfi.NewTokenRole(string(bennyfi.TokenRoleEntryStake), 10000, 1000000, tlos),
+				bennyfi.NewTokenRole(string(bennyfi.TokenRoleTotalReward), 50000, 5000000, tlos),
+			},
+		},
+	})
+}
+
+func TestTokenPolicyValidateNewRoundArgs(t *testing.T) {
+	policy := newTestTokenPolicy()
+
+	err := policy.ValidateNewRoundArgs(&bennyfi.NewRoundArgs{
+		EntryStake:  "10.0000 TLOS",
+		TotalReward: "100.0000 TLOS",
+	})
+	assert.NilError(t, err)
+
+	err = policy.ValidateNewRoundArgs(&bennyfi.NewRoundArgs{
+		EntryStake:  "0.5000 TLOS",
+		TotalReward: "600.0000 TLOS",
+	})
+	violations, ok := err.(bennyfi.TokenViolations)
+	assert.Assert(t, ok)
+	assert.Equal(t, len(violations), 2)
+	assert.Equal(t, violations[0].Field, "entry_stake")
+	assert.Equal(t, violations[1].Field, "total_reward")
+
+	err = policy.ValidateNewRoundArgs(&bennyfi.NewRoundArgs{
+		EntryStake:  "10.00 TLOS",
+		TotalReward: "10.0000 EOS",
+	})
+	violations, ok = err.(bennyfi.TokenViolations)
+	assert.Assert(t, ok)
+	assert.Equal(t, len(violations), 2)
+}
+
+func TestTokenPolicyValidateWithdraw(t *testing.T) {
+	policy := newTestTokenPolicy()
+
+	assert.NilError(t, policy.ValidateWithdraw(eos.Asset{Amount: 10, Symbol: tlos}))
+	assert.ErrorContains(t, policy.ValidateWithdraw(eos.Asset{Amount: 0, Symbol: tlos}), "quantity must be positive")
+	assert.ErrorContains(t, policy.ValidateWithdraw(eos.Asset{Amount: 10, Symbol: eos.Symbol{Precision: 4, Symbol: "EOS"}}), "token is not authorized")
+}
+
+func TestTokenPolicyValidateDeposit(t *testing.T) {
+	policy := newTestTokenPolicy()
+	assert.NilError(t, policy.ValidateDeposit(eos.Asset{Amount: 10000, Symbol: tlos}))
+	assert.ErrorContains(t, policy.ValidateDeposit(eos.Asset{Amount: 0, Symbol: tlos}), "quantity must be positive")
+	assert.ErrorContains(t, policy.ValidateDeposit(eos.Asset{Amount: -10000, Symbol: tlos}), "quantity must be positive")
+	assert.ErrorContains(t, policy.ValidateDeposit(eos.Asset{Amount: 10000, Symbol: eos.Symbol{Precision: 4, Symbol: "EOS"}}), "token is not authorized")
+	assert.ErrorContains(t, policy.ValidateDeposit(eos.Asset{Amount: 10000, Symbol: eos.Symbol{Precision: 8, Symbol: "TLOS"}}), "precision mismatch, expected: 4, found: 8")
+}
+
+func TestTokenPolicyValidateTokenRole(t *testing.T) {
+	policy := newTestTokenPolicy()
+
+	args := &bennyfi.SetTokenRoleArgs{
+		Authorizer:    eos.AN("admin"),
+		TokenContract: eos.AN("eosio.token"),
+		TokenRole:     bennyfi.TokenRoleEntryStake,
+		MinValue:      eos.Asset{Amount: 10, Symbol: tlos},
+		MaxValue:      eos.Asset{Amount: 100, Symbol: tlos},
+	}
+	assert.NilError(t, policy.ValidateTokenRole(args))
+
+	args.MinValue.Amount = 1000
+	assert.ErrorContains(t, policy.ValidateTokenRole(args), "min value is greater than max value")
+
+	args.MinValue = eos.Asset{Amount: 10, Symbol: eos.Symbol{Precision: 2, Symbol: "TLOS"}}
+	assert.ErrorContains(t, policy.ValidateTokenRole(args), "symbol mismatch")
+
+	args.MinValue = eos.Asset{Amount: 10, Symbol: tlos}
+	args.TokenContract = eos.AN("fake.token")
+	assert.ErrorContains(t, policy.ValidateTokenRole(args), "token contract mismatch")
+}
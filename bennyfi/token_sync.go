@@ -0,0 +1,297 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package bennyfi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"gopkg.in/yaml.v2"
+)
+
+// TokenRoleConfig min and max can be specified with or without the symbol code, i.e. "1.5" or "1.5000 TLOS"
+type TokenRoleConfig struct {
+	Role eos.Name `yaml:"role" json:"role"`
+	Min  string   `yaml:"min" json:"min"`
+	Max  string   `yaml:"max" json:"max"`
+}
+
+type TokenConfig struct {
+	Symbol        string             `yaml:"symbol" json:"symbol"`
+	Precision     uint8              `yaml:"precision" json:"precision"`
+	TokenContract eos.AccountName    `yaml:"token_contract" json:"token_contract"`
+	Roles         []*TokenRoleConfig `yaml:"roles" json:"roles"`
+}
+
+func (m *TokenConfig) GetSymbol() eos.Symbol {
+	return eos.Symbol{Precision: m.Precision, Symbol: m.Symbol}
+}
+
+func (m *TokenConfig) AuthToken(authorizer eos.AccountName) (*AuthToken, error) {
+	symbol := m.GetSymbol()
+	tokenRoles := make(TokenRoles, 0, len(m.Roles))
+	for _, role := range m.Roles {
+		min, err := eos.NewFixedSymbolAssetFromString(symbol, role.Min)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min value: %v for token: %v role: %v, error: %v", role.Min, symbol, role.Role, err)
+		}
+		max, err := eos.NewFixedSymbolAssetFromString(symbol, role.Max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max value: %v for token: %v role: %v, error: %v", role.Max, symbol, role.Role, err)
+		}
+		tokenRoles = append(tokenRoles, NewTokenRole(string(role.Role), min.Amount, max.Amount, symbol))
+	}
+	authToken := &AuthToken{
+		Authorizer:    authorizer,
+		Symbol:        symbol.String(),
+		TokenContract: m.TokenContract,
+		TokenRoles:    tokenRoles,
+	}
+	err := ValidateAuthToken(authToken)
+	if err != nil {
+		return nil, err
+	}
+	return authToken, nil
+}
+
+// TokensConfig is the declarative definition of the tokens accepted by the bennyfi contract
+type TokensConfig struct {
+	Authorizer eos.AccountName `yaml:"authorizer" json:"authorizer"`
+	Tokens     []*TokenConfig  `yaml:"tokens" json:"tokens"`
+}
+
+func LoadTokensConfig(file string) (*TokensConfig, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading tokens config file: %v, error: %v", file, err)
+	}
+	config := &TokensConfig{}
+	err = yaml.UnmarshalStrict(data, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing tokens config file: %v, error: %v", file, err)
+	}
+	return config, nil
+}
+
+// TokenSyncOp is a single action required to converge the authtokens table to the tokens config
+type TokenSyncOp struct {
+	Action     eos.ActionName
+	Authorizer eos.AccountName
+	Symbol     eos.Symbol
+	AuthToken  *AuthToken
+	RoleArgs   *SetTokenRoleArgs
+	TokenRole  eos.Name
+}
+
+// ContractAction builds the action of the op with the same builders used by SetToken, SetTokenRole,
+// EraseTokenRole and EraseToken
+func (m *TokenSyncOp) ContractAction(contract *BennyfiContract) (*ContractAction, error) {
+	switch m.Action {
+	case "settoken":
+		return contract.SetTokenAction(m.AuthToken), nil
+	case "settokenrole":
+		return contract.SetTokenRoleAction(m.RoleArgs), nil
+	case "erasetknrole":
+		return contract.EraseTokenRoleAction(m.Authorizer, m.Symbol, m.TokenRole), nil
+	case "erasetoken":
+		return contract.EraseTokenAction(m.Authorizer, m.Symbol), nil
+	default:
+		return nil, fmt.Errorf("unknown token sync action: %v", m.Action)
+	}
+}
+
+func (m *TokenSyncOp) String() string {
+	switch m.Action {
+	case "settoken":
+		roles := make([]string, 0, len(m.AuthToken.TokenRoles))
+		for _, role := range m.AuthToken.TokenRoles {
+			roles = append(roles, fmt.Sprintf("%v[%v, %v]", role.Key, role.Value.MinValue, role.Value.MaxValue))
+		}
+		return fmt.Sprintf("settoken symbol: %v, token contract: %v, roles: %v", m.Symbol, m.AuthToken.TokenContract, strings.Join(roles, " "))
+	case "settokenrole":
+		return fmt.Sprintf("settokenrole symbol: %v, role: %v, min: %v, max: %v", m.Symbol, m.RoleArgs.TokenRole, m.RoleArgs.MinValue, m.RoleArgs.MaxValue)
+	case "erasetknrole":
+		return fmt.Sprintf("erasetknrole symbol: %v, role: %v", m.Symbol, m.TokenRole)
+	default:
+		return fmt.Sprintf("%v symbol: %v", m.Action, m.Symbol)
+	}
+}
+
+type TokenSyncPlan []*TokenSyncOp
+
+func (m TokenSyncPlan) String() string {
+	if len(m) == 0 {
+		return "tokens are in sync, no changes required"
+	}
+	ops := make([]string, 0, len(m))
+	for i, op := range m {
+		ops = append(ops, fmt.Sprintf("%v. %v", i+1, op))
+	}
+	return strings.Join(ops, "\n")
+}
+
+// PlanTokenSync computes the actions required for the current authtokens to match the tokens config,
+// tokens not present in the config are erased
+func PlanTokenSync(config *TokensConfig, current []AuthToken) (TokenSyncPlan, error) {
+	plan := make(TokenSyncPlan, 0)
+	currentTokens := NewTokenPolicy(current).Tokens
+	desiredTokens := make(map[string]bool)
+	for _, tokenConfig := range config.Tokens {
+		symbol := tokenConfig.GetSymbol()
+		if desiredTokens[symbol.Symbol] {
+			return nil, fmt.Errorf("token: %v is defined more than once", symbol.Symbol)
+		}
+		desiredTokens[symbol.Symbol] = true
+		desired, err := tokenConfig.AuthToken(config.Authorizer)
+		if err != nil {
+			return nil, err
+		}
+		currentToken := currentTokens[symbol.Symbol]
+		if currentToken != nil && (currentToken.Symbol != desired.Symbol || currentToken.TokenContract != desired.TokenContract) {
+			currentSymbol, err := currentToken.GetSymbol()
+			if err != nil {
+				return nil, fmt.Errorf("invalid symbol: %v in authtokens table, error: %v", currentToken.Symbol, err)
+			}
+			plan = append(plan, &TokenSyncOp{
+				Action:     "erasetoken",
+				Authorizer: config.Authorizer,
+				Symbol:     currentSymbol,
+			})
+			currentToken = nil
+		}
+		if currentToken == nil {
+			plan = append(plan, &TokenSyncOp{
+				Action:     "settoken",
+				Authorizer: config.Authorizer,
+				Symbol:     symbol,
+				AuthToken:  desired,
+			})
+			continue
+		}
+		rolePlan, err := planTokenRolesSync(config.Authorizer, symbol, desired, currentToken)
+		if err != nil {
+			return nil, err
+		}
+		plan = append(plan, rolePlan...)
+	}
+	for _, authToken := range current {
+		if !desiredTokens[symbolCode(authToken.Symbol)] {
+			symbol, err := authToken.GetSymbol()
+			if err != nil {
+				return nil, fmt.Errorf("invalid symbol: %v in authtokens table, error: %v", authToken.Symbol, err)
+			}
+			plan = append(plan, &TokenSyncOp{
+				Action:     "erasetoken",
+				Authorizer: config.Authorizer,
+				Symbol:     symbol,
+			})
+		}
+	}
+	return plan, nil
+}
+
+func planTokenRolesSync(authorizer eos.AccountName, symbol eos.Symbol, desired, current *AuthToken) (TokenSyncPlan, error) {
+	plan := make(TokenSyncPlan, 0)
+	for _, role := range desired.TokenRoles {
+		currentRole := current.GetTokenRole(role.Key)
+		if currentRole != nil && currentRole.Value != nil &&
+			sameAssetString(currentRole.Value.MinValue, role.Value.MinValue) &&
+			sameAssetString(currentRole.Value.MaxValue, role.Value.MaxValue) {
+			continue
+		}
+		min, _ := role.Value.Min()
+		max, _ := role.Value.Max()
+		plan = append(plan, &TokenSyncOp{
+			Action:     "settokenrole",
+			Authorizer: authorizer,
+			Symbol:     symbol,
+			RoleArgs: &SetTokenRoleArgs{
+				Authorizer:    authorizer,
+				TokenContract: desired.TokenContract,
+				TokenRole:     role.Key,
+				MinValue:      min,
+				MaxValue:      max,
+			},
+		})
+	}
+	for _, role := range current.TokenRoles {
+		if desired.GetTokenRole(role.Key) == nil {
+			plan = append(plan, &TokenSyncOp{
+				Action:     "erasetknrole",
+				Authorizer: authorizer,
+				Symbol:     symbol,
+				TokenRole:  role.Key,
+			})
+		}
+	}
+	return plan, nil
+}
+
+func sameAssetString(a1, a2 string) bool {
+	asset1, err := eos.NewAssetFromString(a1)
+	if err != nil {
+		return false
+	}
+	asset2, err := eos.NewAssetFromString(a2)
+	if err != nil {
+		return false
+	}
+	return asset1.Amount == asset2.Amount && sameSymbol(asset1.Symbol, asset2.Symbol)
+}
+
+func (m *BennyfiContract) PlanTokenSync(config *TokensConfig) (TokenSyncPlan, error) {
+	current, err := m.GetTokens()
+	if err != nil {
+		return nil, fmt.Errorf("failed loading current tokens, error: %v", err)
+	}
+	return PlanTokenSync(config, current)
+}
+
+func (m *BennyfiContract) ApplyTokenSyncPlan(plan TokenSyncPlan) error {
+	for _, op := range plan {
+		action, err := op.ContractAction(m)
+		if err != nil {
+			return fmt.Errorf("failed applying token sync op: %v, error: %v", op, err)
+		}
+		_, err = m.Exec(action)
+		if err != nil {
+			return fmt.Errorf("failed applying token sync op: %v, error: %v", op, err)
+		}
+	}
+	return nil
+}
+
+// ProposeTokenSyncPlan creates a single multisig proposal containing all the actions of the plan
+func (m *BennyfiContract) ProposeTokenSyncPlan(proposerName interface{}, requested []eos.PermissionLevel, expireIn time.Duration, plan TokenSyncPlan) (string, error) {
+	actions := make([]*ContractAction, 0, len(plan))
+	for _, op := range plan {
+		action, err := op.ContractAction(m)
+		if err != nil {
+			return "", fmt.Errorf("failed proposing token sync op: %v, error: %v", op, err)
+		}
+		actions = append(actions, action)
+	}
+	return m.Propose(proposerName, requested, expireIn, actions...)
+}
@@ -0,0 +1,84 @@
+package bennyfi_test
+
+import (
+	"testing"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+	"gotest.tools/assert"
+)
+
+func TestPlanTokenSync(t *testing.T) {
+	current := []bennyfi.AuthToken{
+		{
+			Authorizer:    eos.AN("admin"),
+			Symbol:        tlos.String(),
+			TokenContract: eos.AN("eosio.token"),
+			TokenRoles: bennyfi.TokenRoles{
+				bennyfi.NewTokenRole(string(bennyfi.TokenRoleEntryStake), 10000, 1000000, tlos),
+				bennyfi.NewTokenRole("oldrole", 1, 2, tlos),
+			},
+		},
+		{
+			Authorizer:    eos.AN("admin"),
+			Symbol:        "4,EOS",
+			TokenContract: eos.AN("eosio.token"),
+		},
+	}
+	config := &bennyfi.TokensConfig{
+		Authorizer: eos.AN("admin"),
+		Tokens: []*bennyfi.TokenConfig{
+			{
+				Symbol:        "TLOS",
+				Precision:     4,
+				TokenContract: eos.AN("eosio.token"),
+				Roles: []*bennyfi.TokenRoleConfig{
+					{Role: bennyfi.TokenRoleEntryStake, Min: "1", Max: "100.0000 TLOS"},
+					{Role: bennyfi.TokenRoleTotalReward, Min: "5", Max: "500"},
+				},
+			},
+			{
+				Symbol:        "USDT",
+				Precision:     2,
+				TokenContract: eos.AN("tethertether"),
+				Roles: []*bennyfi.TokenRoleConfig{
+					{Role: bennyfi.TokenRoleEntryStake, Min: "1", Max: "10"},
+				},
+			},
+		},
+	}
+	plan, err := bennyfi.PlanTokenSync(config, current)
+	assert.NilError(t, err)
+	assert.Equal(t, len(plan), 4, plan.String())
+	assert.Equal(t, plan[0].Action, eos.ActN("settokenrole"))
+	assert.Equal(t, plan[0].RoleArgs.TokenRole, bennyfi.TokenRoleTotalReward)
+	assert.Equal(t, plan[0].RoleArgs.MinValue.String(), "5.0000 TLOS")
+	assert.Equal(t, plan[1].Action, eos.ActN("erasetknrole"))
+	assert.Equal(t, plan[1].TokenRole, eos.Name("oldrole"))
+	assert.Equal(t, plan[2].Action, eos.ActN("settoken"))
+	assert.Equal(t, plan[2].AuthToken.Symbol, "2,USDT")
+	assert.Equal(t, plan[3].Action, eos.ActN("erasetoken"))
+	assert.Equal(t, plan[3].Symbol.Symbol, "EOS")
+
+	config.Tokens[0].Roles[0].Min = "1000"
+	_, err = bennyfi.PlanTokenSync(config, current)
+	assert.ErrorContains(t, err, "min value is greater than max value")
+}
+
+func TestTokenSyncOpContractAction(t *testing.T) {
+	contract := newTestContract(t)
+	symbol := eos.Symbol{Precision: 4, Symbol: "TLOS"}
+	op := &bennyfi.TokenSyncOp{Action: "erasetknrole", Authorizer: "admin", Symbol: symbol, TokenRole: bennyfi.TokenRoleEntryStake}
+	action, err := op.ContractAction(contract)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, action, contract.EraseTokenRoleAction("admin", symbol, bennyfi.TokenRoleEntryStake))
+
+	op = &bennyfi.TokenSyncOp{Action: "erasetoken", Authorizer: "admin", Symbol: symbol}
+	action, err = op.ContractAction(contract)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, action, contract.EraseTokenAction("admin", symbol))
+
+	op.Action = "unknown"
+	_, err = op.ContractAction(contract)
+	assert.ErrorContains(t, err, "unknown token sync action: unknown")
+}
@@ -0,0 +1,295 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package bennyfi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+)
+
+var (
+	// ClaimReturnStates are the round states in which staked entries can claim their return
+	ClaimReturnStates = []eos.Name{RoundUnlocked}
+	// UnstakeStates are the round states in which staked entries are unstaked by the withdraw planner
+	UnstakeStates = []eos.Name{RoundTimedOut}
+	// UnstakeOpenStates are the round states in which staked entries can also be unstaked, leaving a round that
+	// is still accepting entries, the withdraw planner only unstakes from them when explicitly requested
+	UnstakeOpenStates = []eos.Name{RoundAcceptingEntries}
+	// RexLockedStates are the rex states in which the stake is held in REX, it is only released after the REX
+	// matures and is sold
+	RexLockedStates = []eos.Name{RexStateInSavings, RexStateInLockPeriod}
+)
+
+// LockedStake is an amount held in the staked balance of a token holder, either the stake of an entry or the
+// reward of a manager funded round. ReleaseAction is the action that releases it now, empty if it can not be
+// released yet, in which case UnlockTime is the earliest time it can be expected to free up, zero if unknown.
+// UnlockTimeIsLowerBound is set when the stake is held in REX, as the REX maturity and sale are not known in
+// advance the stake may free up later than UnlockTime
+type LockedStake struct {
+	EntryID                uint64
+	RoundID                uint64
+	IsReward               bool
+	Amount                 eos.Asset
+	RoundState             eos.Name
+	RexState               eos.Name
+	UnlockTime             time.Time
+	UnlockTimeIsLowerBound bool
+	ReleaseAction          eos.ActionName
+}
+
+func (m *LockedStake) String() string {
+	source := fmt.Sprintf("entry: %v", m.EntryID)
+	if m.IsReward {
+		source = "reward"
+	}
+	status := fmt.Sprintf("releasable now with %v", m.ReleaseAction)
+	if m.ReleaseAction == "" {
+		status = "unlock time unknown"
+		if !m.UnlockTime.IsZero() {
+			status = fmt.Sprintf("unlocks at %v", m.UnlockTime.UTC().Format(time.RFC3339))
+			if m.UnlockTimeIsLowerBound {
+				status = fmt.Sprintf("unlocks not before %v, pending rex maturity", m.UnlockTime.UTC().Format(time.RFC3339))
+			}
+		}
+	}
+	return fmt.Sprintf("round: %v %v amount: %v, round state: %v, rex state: %v, %v", m.RoundID, source, m.Amount, m.RoundState, m.RexState, status)
+}
+
+// WithdrawPlan describes the funds of a token holder for a symbol, Steps are the release actions that have to be
+// executed before the requested quantity can be withdrawn, Shortfall is the amount that can not be withdrawn
+// even after executing the steps
+type WithdrawPlan struct {
+	TokenHolder eos.AccountName
+	Requested   eos.Asset
+	Liquid      eos.Asset
+	Staked      eos.Asset
+	Releasable  eos.Asset
+	Locked      []*LockedStake
+	Steps       []*LockedStake
+	Shortfall   eos.Asset
+}
+
+func (m *WithdrawPlan) CanWithdraw() bool {
+	return m.Shortfall.Amount == 0
+}
+
+func (m *WithdrawPlan) String() string {
+	lines := []string{
+		fmt.Sprintf("holder: %v requested: %v, withdrawable now: %v, staked: %v, releasable now: %v, shortfall: %v",
+			m.TokenHolder, m.Requested, m.Liquid, m.Staked, m.Releasable, m.Shortfall),
+	}
+	for _, locked := range m.Locked {
+		lines = append(lines, fmt.Sprintf("  %v", locked))
+	}
+	for i, step := range m.Steps {
+		lines = append(lines, fmt.Sprintf("  step %v. %v entry: %v", i+1, step.ReleaseAction, step.EntryID))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PlanWithdraw computes how much of the requested quantity can be withdrawn now and which entries have to be
+// released first, balance can be nil if the holder has no balance, rounds must contain the rounds of the entries.
+// Entries in rounds that are still accepting entries are only unstaked if unstakeOpen is true.
+func PlanWithdraw(holder eos.AccountName, quantity eos.Asset, balance *Balance, entries []Entry, rounds map[uint64]*Round, unstakeOpen bool) (*WithdrawPlan, error) {
+	zero := eos.Asset{Amount: 0, Symbol: quantity.Symbol}
+	plan := &WithdrawPlan{
+		TokenHolder: holder,
+		Requested:   quantity,
+		Liquid:      zero,
+		Staked:      zero,
+		Releasable:  zero,
+		Shortfall:   zero,
+	}
+	if balance != nil {
+		liquid, err := balance.GetLiquidBalance()
+		if err != nil {
+			return nil, err
+		}
+		staked, err := balance.GetStakedBalance()
+		if err != nil {
+			return nil, err
+		}
+		if !sameSymbol(liquid.Symbol, quantity.Symbol) {
+			return nil, fmt.Errorf("balance symbol: %v does not match requested symbol: %v", liquid.Symbol, quantity.Symbol)
+		}
+		plan.Liquid = liquid
+		plan.Staked = staked
+	}
+	for _, entry := range entries {
+		if entry.Participant != holder || entry.EntryStatus != EntryStaked {
+			continue
+		}
+		round := rounds[entry.RoundID]
+		if round == nil {
+			return nil, fmt.Errorf("round: %v of entry: %v not found", entry.RoundID, entry.EntryID)
+		}
+		stake, err := eos.NewAssetFromString(entry.EntryStake)
+		if err != nil {
+			return nil, fmt.Errorf("entry: %v has invalid entry stake: %v, error: %v", entry.EntryID, entry.EntryStake, err)
+		}
+		if !sameSymbol(stake.Symbol, quantity.Symbol) {
+			continue
+		}
+		locked, err := newLockedStake(round, stake)
+		if err != nil {
+			return nil, err
+		}
+		locked.EntryID = entry.EntryID
+		if containsName(ClaimReturnStates, round.CurrentState) {
+			locked.ReleaseAction = "claimreturn"
+		} else if containsName(UnstakeStates, round.CurrentState) ||
+			(unstakeOpen && containsName(UnstakeOpenStates, round.CurrentState)) {
+			locked.ReleaseAction = "unstake"
+		}
+		plan.Locked = append(plan.Locked, locked)
+	}
+	roundIDs := make([]uint64, 0, len(rounds))
+	for roundID := range rounds {
+		roundIDs = append(roundIDs, roundID)
+	}
+	sort.Slice(roundIDs, func(i, j int) bool {
+		return roundIDs[i] < roundIDs[j]
+	})
+	for _, roundID := range roundIDs {
+		round := rounds[roundID]
+		if round.RoundManager != holder || round.RoundType != RoundTypeManagerFunded || !containsName(RewardHeldStates, round.CurrentState) {
+			continue
+		}
+		reward, err := eos.NewAssetFromString(round.TotalReward)
+		if err != nil {
+			return nil, fmt.Errorf("round: %v has invalid total reward: %v, error: %v", round.RoundID, round.TotalReward, err)
+		}
+		if !sameSymbol(reward.Symbol, quantity.Symbol) {
+			continue
+		}
+		locked, err := newLockedStake(round, reward)
+		if err != nil {
+			return nil, err
+		}
+		locked.IsReward = true
+		plan.Locked = append(plan.Locked, locked)
+	}
+	// releasable stakes first, then by unlock time with unknown unlock times last
+	sort.SliceStable(plan.Locked, func(i, j int) bool {
+		li, lj := plan.Locked[i], plan.Locked[j]
+		if (li.ReleaseAction != "") != (lj.ReleaseAction != "") {
+			return li.ReleaseAction != ""
+		}
+		if li.UnlockTime.IsZero() != lj.UnlockTime.IsZero() {
+			return !li.UnlockTime.IsZero()
+		}
+		return li.UnlockTime.Before(lj.UnlockTime)
+	})
+	for _, locked := range plan.Locked {
+		if locked.ReleaseAction != "" {
+			plan.Releasable.Amount += locked.Amount.Amount
+		}
+	}
+	missing := quantity.Amount - plan.Liquid.Amount
+	for _, locked := range plan.Locked {
+		if missing <= 0 {
+			break
+		}
+		if locked.ReleaseAction != "" {
+			plan.Steps = append(plan.Steps, locked)
+			missing -= locked.Amount.Amount
+		}
+	}
+	if missing > 0 {
+		plan.Shortfall.Amount = missing
+	}
+	return plan, nil
+}
+
+func newLockedStake(round *Round, amount eos.Asset) (*LockedStake, error) {
+	unlockTime, err := round.GetStakeEndTime()
+	if err != nil {
+		return nil, fmt.Errorf("round: %v, %v", round.RoundID, err)
+	}
+	return &LockedStake{
+		RoundID:                round.RoundID,
+		Amount:                 amount,
+		RoundState:             round.CurrentState,
+		RexState:               round.RexState,
+		UnlockTime:             unlockTime,
+		UnlockTimeIsLowerBound: containsName(RexLockedStates, round.RexState),
+	}, nil
+}
+
+func (m *BennyfiContract) PlanWithdraw(holder eos.AccountName, quantity eos.Asset, unstakeOpen bool) (*WithdrawPlan, error) {
+	balance, err := m.GetBalance(holder, quantity.Symbol.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed getting balance of: %v, error: %v", holder, err)
+	}
+	entries, err := m.GetAllEntriesbyParticipant(holder)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting entries of: %v, error: %v", holder, err)
+	}
+	rounds := make(map[uint64]*Round)
+	for _, entry := range entries {
+		if _, ok := rounds[entry.RoundID]; ok || entry.EntryStatus != EntryStaked {
+			continue
+		}
+		round, err := m.GetRound(entry.RoundID)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting round: %v, error: %v", entry.RoundID, err)
+		}
+		if round != nil {
+			rounds[round.RoundID] = round
+		}
+	}
+	managedRounds, err := m.GetAllRoundsbyManager(holder)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting rounds managed by: %v, error: %v", holder, err)
+	}
+	for i := range managedRounds {
+		rounds[managedRounds[i].RoundID] = &managedRounds[i]
+	}
+	return PlanWithdraw(holder, quantity, balance, entries, rounds, unstakeOpen)
+}
+
+// ExecuteWithdrawPlan runs the release steps of the plan and then withdraws the requested quantity, fails
+// without executing anything if the plan has a shortfall
+func (m *BennyfiContract) ExecuteWithdrawPlan(plan *WithdrawPlan) (string, error) {
+	if !plan.CanWithdraw() {
+		return "", fmt.Errorf("can not withdraw: %v, shortfall: %v", plan.Requested, plan.Shortfall)
+	}
+	for _, step := range plan.Steps {
+		var err error
+		switch step.ReleaseAction {
+		case "claimreturn":
+			_, err = m.ClaimReturn(step.EntryID, plan.TokenHolder)
+		case "unstake":
+			_, err = m.Unstake(step.EntryID, plan.TokenHolder)
+		default:
+			err = fmt.Errorf("unknown release action: %v", step.ReleaseAction)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed releasing entry: %v with %v, error: %v", step.EntryID, step.ReleaseAction, err)
+		}
+	}
+	return m.Withdraw(plan.TokenHolder, plan.Requested)
+}
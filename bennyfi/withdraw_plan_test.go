@@ -0,0 +1,72 @@
+package bennyfi_test
+
+import (
+	"testing"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/bennyfi"
+	"gotest.tools/assert"
+)
+
+func TestPlanWithdraw(t *testing.T) {
+	balance := &bennyfi.Balance{TokenHolder: "alice", Symbol: tlos.String(), LiquidBalance: "5.0000 TLOS", StakedBalance: "30.0000 TLOS"}
+	rounds := map[uint64]*bennyfi.Round{
+		1: {RoundID: 1, CurrentState: bennyfi.RoundUnlocked, StakeEndTime: "2021-01-01T00:00:00.000"},
+		2: {RoundID: 2, CurrentState: bennyfi.RoundOpen, RexState: bennyfi.RexStateInLockPeriod, StakeEndTime: "2021-02-01T00:00:00.000"},
+		3: {RoundID: 3, CurrentState: bennyfi.RoundTimedOut, StakeEndTime: "1970-01-01T00:00:00.000"},
+	}
+	entries := []bennyfi.Entry{
+		{EntryID: 10, RoundID: 2, Participant: "alice", EntryStake: "10.0000 TLOS", EntryStatus: bennyfi.EntryStaked},
+		{EntryID: 11, RoundID: 1, Participant: "alice", EntryStake: "10.0000 TLOS", EntryStatus: bennyfi.EntryStaked},
+		{EntryID: 12, RoundID: 3, Participant: "alice", EntryStake: "10.0000 TLOS", EntryStatus: bennyfi.EntryStaked},
+		{EntryID: 13, RoundID: 1, Participant: "alice", EntryStake: "10.0000 TLOS", EntryStatus: bennyfi.EntryReturnPaid},
+	}
+
+	plan, err := bennyfi.PlanWithdraw("alice", eos.Asset{Amount: 120000, Symbol: tlos}, balance, entries, rounds, false)
+	assert.NilError(t, err)
+	assert.Assert(t, plan.CanWithdraw(), plan.String())
+	assert.Equal(t, plan.Releasable.String(), "20.0000 TLOS")
+	assert.Equal(t, len(plan.Locked), 3)
+	assert.Equal(t, plan.Locked[2].EntryID, uint64(10))
+	assert.Equal(t, plan.Locked[2].UnlockTime, time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC))
+	assert.Assert(t, plan.Locked[2].UnlockTimeIsLowerBound)
+	assert.Assert(t, !plan.Locked[0].UnlockTimeIsLowerBound)
+	assert.Equal(t, len(plan.Steps), 1)
+	assert.Equal(t, plan.Steps[0].ReleaseAction, eos.ActN("claimreturn"))
+
+	plan, err = bennyfi.PlanWithdraw("alice", eos.Asset{Amount: 300000, Symbol: tlos}, balance, entries, rounds, false)
+	assert.NilError(t, err)
+	assert.Assert(t, !plan.CanWithdraw())
+	assert.Equal(t, len(plan.Steps), 2)
+	assert.Equal(t, plan.Shortfall.String(), "5.0000 TLOS")
+}
+
+func TestPlanWithdrawUnstakeOpen(t *testing.T) {
+	balance := &bennyfi.Balance{TokenHolder: "alice", Symbol: tlos.String(), LiquidBalance: "0.0000 TLOS", StakedBalance: "20.0000 TLOS"}
+	rounds := map[uint64]*bennyfi.Round{
+		1: {RoundID: 1, CurrentState: bennyfi.RoundAcceptingEntries, StakeEndTime: "1970-01-01T00:00:00.000"},
+		2: {RoundID: 2, CurrentState: bennyfi.RoundTimedOut, StakeEndTime: "1970-01-01T00:00:00.000"},
+	}
+	entries := []bennyfi.Entry{
+		{EntryID: 10, RoundID: 1, Participant: "alice", EntryStake: "10.0000 TLOS", EntryStatus: bennyfi.EntryStaked},
+		{EntryID: 11, RoundID: 2, Participant: "alice", EntryStake: "10.0000 TLOS", EntryStatus: bennyfi.EntryStaked},
+	}
+	quantity := eos.Asset{Amount: 200000, Symbol: tlos}
+
+	plan, err := bennyfi.PlanWithdraw("alice", quantity, balance, entries, rounds, false)
+	assert.NilError(t, err)
+	assert.Equal(t, plan.Releasable.String(), "10.0000 TLOS")
+	assert.Equal(t, plan.Shortfall.String(), "10.0000 TLOS")
+	assert.Equal(t, len(plan.Steps), 1)
+	assert.Equal(t, plan.Steps[0].EntryID, uint64(11))
+
+	plan, err = bennyfi.PlanWithdraw("alice", quantity, balance, entries, rounds, true)
+	assert.NilError(t, err)
+	assert.Assert(t, plan.CanWithdraw(), plan.String())
+	assert.Equal(t, len(plan.Steps), 2)
+
+	rounds[2].StakeEndTime = "not a time"
+	_, err = bennyfi.PlanWithdraw("alice", quantity, balance, entries, rounds, false)
+	assert.ErrorContains(t, err, "round: 2, invalid stake end time: not a time")
+}
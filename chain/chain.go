@@ -0,0 +1,117 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package chain holds the settings of the chains the contract clients can be deployed on
+package chain
+
+import (
+	"fmt"
+
+	"github.com/eoscanada/eos-go"
+)
+
+// Chain holds the chain specific settings used by the contract clients. The presets are returned by value,
+// each caller gets its own copy which can be adjusted before construction, i.e. to point to a mock rex contract
+type Chain struct {
+	Name            string
+	CoreSymbol      eos.Symbol
+	TokenContract   eos.AccountName
+	RexSymbol       eos.Symbol
+	RexContract     eos.AccountName
+	CrankPermission eos.PermissionName
+}
+
+func Telos() Chain {
+	return newChain("telos", eos.Symbol{Precision: 4, Symbol: "TLOS"})
+}
+
+func TelosTestnet() Chain {
+	chain := Telos()
+	chain.Name = "telos-testnet"
+	return chain
+}
+
+func EOS() Chain {
+	return newChain("eos", eos.Symbol{Precision: 4, Symbol: "EOS"})
+}
+
+func WAX() Chain {
+	return newChain("wax", eos.Symbol{Precision: 8, Symbol: "WAX"})
+}
+
+func newChain(name string, coreSymbol eos.Symbol) Chain {
+	return Chain{
+		Name:            name,
+		CoreSymbol:      coreSymbol,
+		TokenContract:   eos.AN("eosio.token"),
+		RexSymbol:       eos.Symbol{Precision: 4, Symbol: "REX"},
+		RexContract:     eos.AN("eosio"),
+		CrankPermission: eos.PermissionName("open"),
+	}
+}
+
+// Validate checks that all the settings of the chain are set, it is called by the contract constructors
+func (m Chain) Validate() error {
+	if m.CoreSymbol.Symbol == "" {
+		return fmt.Errorf("chain: %v has no core symbol", m.Name)
+	}
+	if m.TokenContract == "" {
+		return fmt.Errorf("chain: %v has no token contract", m.Name)
+	}
+	if m.RexSymbol.Symbol == "" {
+		return fmt.Errorf("chain: %v has no rex symbol", m.Name)
+	}
+	if m.RexContract == "" {
+		return fmt.Errorf("chain: %v has no rex contract", m.Name)
+	}
+	if m.CrankPermission == "" {
+		return fmt.Errorf("chain: %v has no crank permission", m.Name)
+	}
+	return nil
+}
+
+// CoreAsset returns an asset of the core symbol, amount is expressed in the smallest unit
+func (m Chain) CoreAsset(amount int64) eos.Asset {
+	return eos.Asset{Amount: eos.Int64(amount), Symbol: m.CoreSymbol}
+}
+
+// RexAsset returns an asset of the rex symbol, amount is expressed in the smallest unit
+func (m Chain) RexAsset(amount int64) eos.Asset {
+	return eos.Asset{Amount: eos.Int64(amount), Symbol: m.RexSymbol}
+}
+
+// CheckCoreAsset errors if the asset is not of the core symbol
+func (m Chain) CheckCoreAsset(asset eos.Asset) error {
+	return checkSymbol(asset, m.CoreSymbol, "core")
+}
+
+// CheckRexAsset errors if the asset is not of the rex symbol
+func (m Chain) CheckRexAsset(asset eos.Asset) error {
+	return checkSymbol(asset, m.RexSymbol, "rex")
+}
+
+func checkSymbol(asset eos.Asset, symbol eos.Symbol, kind string) error {
+	if asset.Symbol.Symbol != symbol.Symbol || asset.Symbol.Precision != symbol.Precision {
+		return fmt.Errorf("asset: %v does not match %v symbol: %v", asset, kind, symbol)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package chain_test
+
+import (
+	"testing"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/chain"
+	"gotest.tools/assert"
+)
+
+func TestChainPresets(t *testing.T) {
+	for _, preset := range []chain.Chain{chain.Telos(), chain.TelosTestnet(), chain.EOS(), chain.WAX()} {
+		assert.NilError(t, preset.Validate(), preset.Name)
+	}
+	wax := chain.WAX()
+	assert.Equal(t, wax.CoreAsset(100000000).String(), "1.00000000 WAX")
+	assert.Equal(t, wax.RexAsset(10000).String(), "1.0000 REX")
+
+	telos := chain.Telos()
+	telos.RexContract = eos.AN("rexmock")
+	telos.CoreSymbol = eos.Symbol{Precision: 2, Symbol: "TST"}
+	assert.Equal(t, chain.Telos().RexContract, eos.AN("eosio"))
+	assert.Equal(t, chain.Telos().CoreAsset(10000).String(), "1.0000 TLOS")
+
+	assert.Equal(t, chain.Telos().TokenContract, eos.AN("eosio.token"))
+	assert.NilError(t, wax.CheckCoreAsset(wax.CoreAsset(1)))
+	assert.ErrorContains(t, wax.CheckCoreAsset(chain.EOS().CoreAsset(1)), "does not match core symbol")
+	assert.ErrorContains(t, wax.CheckRexAsset(wax.CoreAsset(1)), "does not match rex symbol")
+
+	telos.CrankPermission = ""
+	assert.ErrorContains(t, telos.Validate(), "has no crank permission")
+	telos = chain.Telos()
+	telos.TokenContract = ""
+	assert.ErrorContains(t, telos.Validate(), "has no token contract")
+}
@@ -5,7 +5,8 @@ go 1.16
 require (
 	github.com/eoscanada/eos-go v0.9.1-0.20200805141443-a9d5402a7bc5
 	github.com/sebastianmontero/eos-go-toolbox v0.0.0-20210713215758-03e6dac09932
-	gotest.tools v2.2.0+incompatible // indirect
+	gopkg.in/yaml.v2 v2.2.2
+	gotest.tools v2.2.0+incompatible
 )
 
 // replace github.com/sebastianmontero/eos-go-toolbox => ../eos-go-toolbox
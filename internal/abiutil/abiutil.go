@@ -0,0 +1,46 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package abiutil holds the ABI decoding helpers shared by the custom binary unmarshallers of the client
+package abiutil
+
+import (
+	eos "github.com/eoscanada/eos-go"
+)
+
+// ReadString reads a length prefixed string. The eos-go decoder allocates the declared length upfront and
+// panics if it exceeds the remaining data, reading the bytes one by one turns that into an error.
+func ReadString(decoder *eos.Decoder) (string, error) {
+	length, err := decoder.ReadUvarint64()
+	if err != nil {
+		return "", err
+	}
+	data := make([]byte, 0)
+	for i := uint64(0); i < length; i++ {
+		b, err := decoder.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		data = append(data, b)
+	}
+	return string(data), nil
+}
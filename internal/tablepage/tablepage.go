@@ -0,0 +1,113 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package tablepage reads contract tables page by page for the GetAll readers of the client
+package tablepage
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/eos-go-toolbox/contract"
+)
+
+// Size is the number of rows requested per page
+const Size = 100
+
+// maxEmptyPages is the number of consecutive empty pages with more rows after which ReadAll gives up
+const maxEmptyPages = 10
+
+// ReadAll calls fetch with the lower bound of each page, starting at the first row, until fetch reports that
+// there are no more rows. fetch returns the number of rows read and the more flag of the response, nodeos can
+// return a short or even empty page with more set when it hits its time limit, so the page size does not
+// end the read. next is called after a non empty page with more rows and returns the lower bound of the
+// following page, usually computed from the last row read, an empty page is requested again.
+func ReadAll(fetch func(lowerBound string) (int, bool, error), next func() (string, error)) error {
+	lowerBound := ""
+	emptyPages := 0
+	for {
+		rows, more, err := fetch(lowerBound)
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+		if rows == 0 {
+			emptyPages++
+			if emptyPages == maxEmptyPages {
+				return fmt.Errorf("no rows read after %v pages from lower bound: %v", maxEmptyPages, lowerBound)
+			}
+			continue
+		}
+		emptyPages = 0
+		lowerBound, err = next()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// GetTableRows reads a page of rows through the chain API and returns the more flag of the response, which
+// the toolbox GetTableRows does not expose. Code and scope default to the contract
+func GetTableRows(c *contract.Contract, request eos.GetTableRowsRequest, rows interface{}) (bool, error) {
+	if request.Code == "" {
+		request.Code = c.ContractName
+	}
+	if request.Scope == "" {
+		request.Scope = c.ContractName
+	}
+	if request.Limit == 0 {
+		request.Limit = Size
+	}
+	request.JSON = true
+	resp, err := c.EOS.API.GetTableRows(context.Background(), request)
+	if err != nil {
+		return false, fmt.Errorf("get table rows %v", err)
+	}
+	err = resp.JSONToStructs(rows)
+	if err != nil {
+		return false, fmt.Errorf("json to structs %v", err)
+	}
+	return resp.More, nil
+}
+
+// AfterID returns the lower bound of the page that follows the row with the numeric primary key
+func AfterID(id uint64) string {
+	return strconv.FormatUint(id+1, 10)
+}
+
+// AfterName returns the lower bound of the page that follows the row with the name primary key
+func AfterName(name string) string {
+	value, _ := eos.StringToName(name)
+	return strconv.FormatUint(value+1, 10)
+}
+
+// ComposedBound returns the i128 bound of the composed index that holds first in the high and second in the
+// low 64 bits
+func ComposedBound(first, second uint64) string {
+	bound := new(big.Int).Lsh(new(big.Int).SetUint64(first), 64)
+	return bound.Add(bound, new(big.Int).SetUint64(second)).String()
+}
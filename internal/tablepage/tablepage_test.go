@@ -0,0 +1,68 @@
+package tablepage_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/sebastianmontero/bennyfi-go-client/internal/tablepage"
+	"gotest.tools/assert"
+)
+
+func TestReadAll(t *testing.T) {
+	table := make([]uint64, 0)
+	for i := uint64(0); i < 2*tablepage.Size+5; i++ {
+		table = append(table, i*2)
+	}
+	rows := make([]uint64, 0)
+	lowerBounds := make([]string, 0)
+	calls := 0
+	err := tablepage.ReadAll(func(lowerBound string) (int, bool, error) {
+		calls++
+		lowerBounds = append(lowerBounds, lowerBound)
+		// the second call times out without rows and the third one returns a short page, as nodeos does when
+		// it hits its time limit
+		limit := tablepage.Size
+		if calls == 2 {
+			limit = 0
+		} else if calls == 3 {
+			limit = 10
+		}
+		bound, _ := strconv.ParseUint(lowerBound, 10, 64)
+		read := 0
+		more := false
+		for _, id := range table {
+			if id < bound {
+				continue
+			}
+			if read == limit {
+				more = true
+				break
+			}
+			rows = append(rows, id)
+			read++
+		}
+		return read, more, nil
+	}, func() (string, error) {
+		return tablepage.AfterID(rows[len(rows)-1]), nil
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, rows, table)
+	assert.DeepEqual(t, lowerBounds, []string{"", "199", "199", "219"})
+
+	err = tablepage.ReadAll(func(lowerBound string) (int, bool, error) {
+		return 0, true, nil
+	}, func() (string, error) {
+		return "", nil
+	})
+	assert.ErrorContains(t, err, "no rows read after")
+}
+
+func TestComposedBound(t *testing.T) {
+	assert.Equal(t, tablepage.ComposedBound(0, 7), "7")
+	assert.Equal(t, tablepage.ComposedBound(1, 2), "18446744073709551618")
+}
+
+func TestAfterName(t *testing.T) {
+	assert.Equal(t, tablepage.AfterName(""), "1")
+	assert.Equal(t, tablepage.AfterName("eosio"), "6138663577826885633")
+}
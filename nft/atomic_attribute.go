@@ -3,12 +3,15 @@ package nft
 import (
 	"fmt"
 	"log"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/internal/abiutil"
 )
 
-var AtomicAttributeVariant = eos.NewVariantDefinition([]eos.VariantType{
+var atomicAttributeTypes = []eos.VariantType{
 	{Name: "int8", Type: int8(0)},
 	{Name: "int16", Type: int16(0)},
 	{Name: "int32", Type: int32(0)},
@@ -31,7 +34,9 @@ var AtomicAttributeVariant = eos.NewVariantDefinition([]eos.VariantType{
 	{Name: "FLOAT_VEC", Type: []float32{}},
 	{Name: "DOUBLE_VEC", Type: []float64{}},
 	{Name: "STRING_VEC", Type: []string{}},
-})
+}
+
+var AtomicAttributeVariant = eos.NewVariantDefinition(atomicAttributeTypes)
 
 type InvalidTypeError struct {
 	Label        string
@@ -45,6 +50,55 @@ func (c *InvalidTypeError) Error() string {
 
 type AttributeMap map[string]*AtomicAttribute
 
+// MarshalBinary encodes the map as a vector of key value pairs sorted by key, so that the encoding is deterministic
+func (m AttributeMap) MarshalBinary(encoder *eos.Encoder) error {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	err := encoder.Encode(eos.Varuint32(len(keys)))
+	if err != nil {
+		return fmt.Errorf("failed encoding attribute map length, error: %v", err)
+	}
+	for _, key := range keys {
+		if m[key] == nil {
+			return fmt.Errorf("attribute: %v has no value", key)
+		}
+		err = encoder.Encode(key)
+		if err != nil {
+			return fmt.Errorf("failed encoding attribute key: %v, error: %v", key, err)
+		}
+		err = encoder.Encode(*m[key])
+		if err != nil {
+			return fmt.Errorf("failed encoding attribute: %v, error: %v", key, err)
+		}
+	}
+	return nil
+}
+
+func (m *AttributeMap) UnmarshalBinary(decoder *eos.Decoder) error {
+	length, err := decoder.ReadUvarint64()
+	if err != nil {
+		return fmt.Errorf("unable to read attribute map length: %v", err)
+	}
+	attributes := make(AttributeMap)
+	for i := uint64(0); i < length; i++ {
+		key, err := abiutil.ReadString(decoder)
+		if err != nil {
+			return fmt.Errorf("unable to read attribute key at position: %v, error: %v", i, err)
+		}
+		attribute := &AtomicAttribute{}
+		err = attribute.UnmarshalBinary(decoder)
+		if err != nil {
+			return fmt.Errorf("unable to read attribute: %v, error: %v", key, err)
+		}
+		attributes[key] = attribute
+	}
+	*m = attributes
+	return nil
+}
+
 type AtomicAttribute struct {
 	*eos.BaseVariant
 }
@@ -300,10 +354,108 @@ func (m *AtomicAttribute) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON translates AtomicAttributeVariant
 func (m *AtomicAttribute) UnmarshalJSON(data []byte) error {
+	if m.BaseVariant == nil {
+		m.BaseVariant = &eos.BaseVariant{}
+	}
 	return m.BaseVariant.UnmarshalJSON(data, AtomicAttributeVariant)
 }
 
-// UnmarshalBinary ...
+// MarshalBinary encodes the attribute as an ABI variant, the type id followed by the value, vectors are
+// encoded as a varuint32 length followed by the elements
+func (m AtomicAttribute) MarshalBinary(encoder *eos.Encoder) error {
+	if m.BaseVariant == nil {
+		return fmt.Errorf("can not encode empty atomic attribute")
+	}
+	if int(m.TypeID) >= len(atomicAttributeTypes) {
+		return fmt.Errorf("unknown atomic attribute type id: %v", m.TypeID)
+	}
+	expectedType := reflect.TypeOf(atomicAttributeTypes[m.TypeID].Type)
+	if reflect.TypeOf(m.Impl) != expectedType {
+		return fmt.Errorf("atomic attribute of type: %v holds a value of type: %T, expected: %v", atomicAttributeTypes[m.TypeID].Name, m.Impl, expectedType)
+	}
+	err := encoder.Encode(eos.Varuint32(m.TypeID))
+	if err != nil {
+		return fmt.Errorf("failed encoding atomic attribute type id, error: %v", err)
+	}
+	err = encoder.Encode(m.Impl)
+	if err != nil {
+		return fmt.Errorf("failed encoding atomic attribute of type: %v, error: %v", atomicAttributeTypes[m.TypeID].Name, err)
+	}
+	return nil
+}
+
+// UnmarshalBinary decodes an ABI variant, values are read explicitly as the eos-go decoder does not support
+// floating point types
 func (m *AtomicAttribute) UnmarshalBinary(decoder *eos.Decoder) error {
-	return m.BaseVariant.UnmarshalBinaryVariant(decoder, AtomicAttributeVariant)
+	typeID, err := decoder.ReadUvarint32()
+	if err != nil {
+		return fmt.Errorf("unable to read atomic attribute type id: %v", err)
+	}
+	if int(typeID) >= len(atomicAttributeTypes) {
+		return fmt.Errorf("unknown atomic attribute type id: %v", typeID)
+	}
+	value, err := decodeAtomicValue(decoder, reflect.TypeOf(atomicAttributeTypes[typeID].Type))
+	if err != nil {
+		return fmt.Errorf("unable to decode atomic attribute of type: %v, error: %v", atomicAttributeTypes[typeID].Name, err)
+	}
+	m.BaseVariant = &eos.BaseVariant{
+		TypeID: typeID,
+		Impl:   value.Interface(),
+	}
+	return nil
+}
+
+func decodeAtomicValue(decoder *eos.Decoder, t reflect.Type) (reflect.Value, error) {
+	var value interface{}
+	var err error
+	switch t.Kind() {
+	case reflect.Int8:
+		value, err = decoder.ReadInt8()
+	case reflect.Int16:
+		value, err = decoder.ReadInt16()
+	case reflect.Int32:
+		value, err = decoder.ReadInt32()
+	case reflect.Int64:
+		value, err = decoder.ReadInt64()
+	case reflect.Uint8:
+		value, err = decoder.ReadUint8()
+	case reflect.Uint16:
+		value, err = decoder.ReadUint16()
+	case reflect.Uint32:
+		value, err = decoder.ReadUint32()
+	case reflect.Uint64:
+		value, err = decoder.ReadUint64()
+	case reflect.Float32:
+		value, err = decoder.ReadFloat32()
+	case reflect.Float64:
+		value, err = decoder.ReadFloat64()
+	case reflect.String:
+		value, err = abiutil.ReadString(decoder)
+	case reflect.Slice:
+		return decodeAtomicVector(decoder, t)
+	default:
+		err = fmt.Errorf("unsupported type: %v", t)
+	}
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(value), nil
+}
+
+// decodeAtomicVector reads the elements one by one instead of preallocating the slice, so that invalid
+// lengths result in an error instead of a huge allocation
+func decodeAtomicVector(decoder *eos.Decoder, t reflect.Type) (reflect.Value, error) {
+	length, err := decoder.ReadUvarint64()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	vector := reflect.MakeSlice(t, 0, 0)
+	for i := uint64(0); i < length; i++ {
+		element, err := decodeAtomicValue(decoder, t.Elem())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("element at position: %v, %v", i, err)
+		}
+		vector = reflect.Append(vector, element)
+	}
+	return vector, nil
 }
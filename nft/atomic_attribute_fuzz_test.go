@@ -0,0 +1,81 @@
+//go:build go1.18
+// +build go1.18
+
+package nft_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/nft"
+)
+
+// FuzzAtomicAttributeBinary checks that any AtomicAttribute that can be decoded is equal to the attribute parsed
+// from its JSON representation, that it can be encoded, that decoding the encoded bytes results in the same JSON
+// representation and that the encoding is canonical
+func FuzzAtomicAttributeBinary(f *testing.F) {
+	f.Add([]byte{0x05, 0x2c, 0x01})
+	f.Add([]byte{0x09, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x04, 0xc0})
+	f.Add([]byte{0x0a, 0x02, 'a', 'b'})
+	f.Add([]byte{0x13, 0x01, 0x00, 0x00, 0xc0, 0x3f})
+	f.Add([]byte{0x15, 0x02, 0x01, 'a', 0x01, 'b'})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		attribute := nft.AtomicAttribute{}
+		if err := eos.UnmarshalBinary(data, &attribute); err != nil {
+			return
+		}
+		expectedJSON, err := json.Marshal(&attribute)
+		if err != nil {
+			// NaN and infinite floats have no JSON representation
+			return
+		}
+		if !validUTF8(attribute.Impl) {
+			// invalid UTF-8 is replaced when encoding to JSON
+			return
+		}
+		fromJSON := nft.AtomicAttribute{}
+		if err := json.Unmarshal(expectedJSON, &fromJSON); err != nil {
+			t.Fatalf("failed parsing json: %v, error: %v", string(expectedJSON), err)
+		}
+		if !reflect.DeepEqual(fromJSON, attribute) {
+			t.Fatalf("binary and json decoded values differ, binary: %#v, json: %#v", attribute.Impl, fromJSON.Impl)
+		}
+		encoded, err := eos.MarshalBinary(attribute)
+		if err != nil {
+			t.Fatalf("failed encoding decoded value: %v, error: %v", string(expectedJSON), err)
+		}
+		actual := nft.AtomicAttribute{}
+		if err := eos.UnmarshalBinary(encoded, &actual); err != nil {
+			t.Fatalf("failed decoding encoded value: %x, error: %v", encoded, err)
+		}
+		actualJSON, err := json.Marshal(&actual)
+		if err != nil {
+			t.Fatalf("failed serializing to json, error: %v", err)
+		}
+		if string(actualJSON) != string(expectedJSON) {
+			t.Fatalf("json mismatch, expected: %v, actual: %v", string(expectedJSON), string(actualJSON))
+		}
+		reencoded, err := eos.MarshalBinary(actual)
+		if err != nil || !bytes.Equal(reencoded, encoded) {
+			t.Fatalf("encoding is not canonical, expected: %x, actual: %x, error: %v", encoded, reencoded, err)
+		}
+	})
+}
+
+func validUTF8(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return utf8.ValidString(v)
+	case []string:
+		for _, s := range v {
+			if !utf8.ValidString(s) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -1,8 +1,12 @@
 package nft_test
 
 import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
 	"testing"
 
+	"github.com/eoscanada/eos-go"
 	"github.com/sebastianmontero/bennyfi-go-client/nft"
 	"gotest.tools/assert"
 )
@@ -52,3 +56,56 @@ func TestToAtomicAttribute(t *testing.T) {
 	assert.Assert(t, actual.IsEqual(expected))
 
 }
+
+func encodeAtomicAttribute(t *testing.T, attribute *nft.AtomicAttribute) []byte {
+	buf := new(bytes.Buffer)
+	assert.NilError(t, eos.NewEncoder(buf).Encode(attribute))
+	return buf.Bytes()
+}
+
+func TestAtomicAttributeBinary(t *testing.T) {
+	assert.Equal(t, hex.EncodeToString(encodeAtomicAttribute(t, nft.NewAtomicAttribute("uint16", uint16(300)))), "052c01")
+	assert.Equal(t, hex.EncodeToString(encodeAtomicAttribute(t, nft.NewAtomicAttribute("FLOAT_VEC", []float32{1.5}))), "13010000c03f")
+	assert.Equal(t, hex.EncodeToString(encodeAtomicAttribute(t, nft.NewAtomicAttribute("STRING_VEC", []string{"ab"}))), "1501026162")
+
+	values := []interface{}{
+		int8(-8), int16(-16), int32(-32), int64(-64), uint8(8), uint16(16), uint32(32), uint64(64),
+		float32(1.25), float64(-2.5), "bennyfi",
+		[]int8{-1, 1}, []int16{-2, 2}, []int32{-3, 3}, []int64{-4, 4},
+		[]uint8{1, 2}, []uint16{2, 3}, []uint32{3, 4}, []uint64{4, 5},
+		[]float32{1.5, -1.5}, []float64{2.5, -2.5}, []string{"a", "b"},
+	}
+	for _, value := range values {
+		attribute := nft.ToAtomicAttribute(value)
+		decoded := &nft.AtomicAttribute{}
+		assert.NilError(t, eos.NewDecoder(encodeAtomicAttribute(t, attribute)).Decode(decoded))
+		assert.Assert(t, decoded.IsEqual(attribute), "type: %T", value)
+	}
+
+	_, err := eos.MarshalBinary(nft.NewAtomicAttribute("int8", int16(1)))
+	assert.ErrorContains(t, err, "holds a value of type")
+	assert.ErrorContains(t, eos.NewDecoder([]byte{0x16}).Decode(&nft.AtomicAttribute{}), "unknown atomic attribute type id")
+	assert.ErrorContains(t, eos.NewDecoder([]byte{0x15, 0xff, 0xff, 0xff, 0xff, 0x0f}).Decode(&nft.AtomicAttribute{}), "unable to decode")
+}
+
+func TestAttributeMapBinary(t *testing.T) {
+	attributes := nft.AttributeMap{
+		"name":  nft.ToAtomicAttribute("winner"),
+		"round": nft.ToAtomicAttribute(uint64(7)),
+	}
+	data, err := eos.MarshalBinary(attributes)
+	assert.NilError(t, err)
+	assert.Equal(t, hex.EncodeToString(data), "02046e616d650a0677696e6e657205726f756e64070700000000000000")
+
+	decoded := nft.AttributeMap{}
+	assert.NilError(t, eos.UnmarshalBinary(data, &decoded))
+	assert.Equal(t, len(decoded), 2)
+	assert.Assert(t, decoded["name"].IsEqual(attributes["name"]))
+	assert.Assert(t, decoded["round"].IsEqual(attributes["round"]))
+}
+
+func TestAtomicAttributeUnmarshalJSON(t *testing.T) {
+	attribute := nft.AtomicAttribute{}
+	assert.NilError(t, json.Unmarshal([]byte(`["uint16", 300]`), &attribute))
+	assert.Equal(t, attribute.Impl, uint16(300))
+}
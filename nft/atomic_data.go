@@ -0,0 +1,523 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package nft
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/eoscanada/eos-go/btcsuite/btcutil/base58"
+	"github.com/sebastianmontero/bennyfi-go-client/internal/abiutil"
+)
+
+// ReservedAttributeIDs is the number of identifiers reserved by AtomicAssets, the attribute at position i
+// of the format is identified by i + ReservedAttributeIDs in the serialized data
+const ReservedAttributeIDs = 4
+
+// atomicFormatVariants maps the AtomicAssets format types to the atomic attribute variant used to hold
+// their values, fixed width types, byte and bool are held as unsigned integers, image and ipfs as strings
+var atomicFormatVariants = map[string]string{
+	"int8":    "int8",
+	"int16":   "int16",
+	"int32":   "int32",
+	"int64":   "int64",
+	"uint8":   "uint8",
+	"uint16":  "uint16",
+	"uint32":  "uint32",
+	"uint64":  "uint64",
+	"fixed8":  "uint8",
+	"byte":    "uint8",
+	"bool":    "uint8",
+	"fixed16": "uint16",
+	"fixed32": "uint32",
+	"fixed64": "uint64",
+	"float":   "float",
+	"double":  "double",
+	"string":  "string",
+	"image":   "string",
+	"ipfs":    "string",
+}
+
+// splitFormatType returns the base type of the format type and whether it is a vector
+func splitFormatType(formatType string) (string, bool) {
+	if strings.HasSuffix(formatType, "[]") {
+		return strings.TrimSuffix(formatType, "[]"), true
+	}
+	return formatType, false
+}
+
+// FormatVariant returns the atomic attribute variant used to hold the values of the AtomicAssets format type
+func FormatVariant(formatType string) (string, error) {
+	baseType, isVector := splitFormatType(formatType)
+	variant, ok := atomicFormatVariants[baseType]
+	if !ok {
+		return "", fmt.Errorf("unknown format type: %v", formatType)
+	}
+	if isVector {
+		return fmt.Sprintf("%v_VEC", strings.ToUpper(variant)), nil
+	}
+	return variant, nil
+}
+
+// DeserializeData decodes AtomicAssets serialized data using the format of the schema or collection
+func DeserializeData(data []uint8, formats []*Format) (AttributeMap, error) {
+	attributes := make(AttributeMap)
+	decoder := eos.NewDecoder(data)
+	for decoder.LastPos() < len(data) {
+		id, err := decoder.ReadUvarint64()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read attribute identifier at position: %v, error: %v", decoder.LastPos(), err)
+		}
+		if id < ReservedAttributeIDs || id-ReservedAttributeIDs >= uint64(len(formats)) {
+			return nil, fmt.Errorf("attribute identifier: %v is not in the format", id)
+		}
+		format := formats[id-ReservedAttributeIDs]
+		attribute, err := deserializeAttribute(decoder, format.Type)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read attribute: %v of type: %v, error: %v", format.Name, format.Type, err)
+		}
+		attributes[format.Name] = attribute
+	}
+	return attributes, nil
+}
+
+func deserializeAttribute(decoder *eos.Decoder, formatType string) (*AtomicAttribute, error) {
+	variant, err := FormatVariant(formatType)
+	if err != nil {
+		return nil, err
+	}
+	baseType, isVector := splitFormatType(formatType)
+	var value reflect.Value
+	if isVector {
+		value, err = deserializeFormatVector(decoder, baseType, reflect.TypeOf(atomicAttributeTypes[AtomicAttributeVariant.TypeID(variant)].Type))
+	} else {
+		value, err = deserializeFormatValue(decoder, baseType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewAtomicAttribute(variant, value.Interface()), nil
+}
+
+// deserializeFormatVector reads the elements one by one instead of preallocating the slice, so that invalid
+// lengths result in an error instead of a huge allocation
+func deserializeFormatVector(decoder *eos.Decoder, baseType string, t reflect.Type) (reflect.Value, error) {
+	length, err := decoder.ReadUvarint64()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	vector := reflect.MakeSlice(t, 0, 0)
+	for i := uint64(0); i < length; i++ {
+		element, err := deserializeFormatValue(decoder, baseType)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("element at position: %v, %v", i, err)
+		}
+		vector = reflect.Append(vector, element)
+	}
+	return vector, nil
+}
+
+// deserializeFormatValue follows the AtomicAssets rules, signed integers are zigzag varints, unsigned integers
+// are varints, fixed width integers and floating point numbers are little endian, strings are length prefixed
+// and ipfs hashes are stored as the length prefixed base58 decoded bytes. Varint values are truncated to the
+// size of the type as done by the contract
+func deserializeFormatValue(decoder *eos.Decoder, baseType string) (reflect.Value, error) {
+	var value interface{}
+	var err error
+	switch baseType {
+	case "int8", "int16", "int32", "int64":
+		var v uint64
+		v, err = decoder.ReadUvarint64()
+		signed := zigzagDecode(v)
+		switch baseType {
+		case "int8":
+			value = int8(signed)
+		case "int16":
+			value = int16(signed)
+		case "int32":
+			value = int32(signed)
+		default:
+			value = signed
+		}
+	case "uint8", "uint16", "uint32", "uint64":
+		var v uint64
+		v, err = decoder.ReadUvarint64()
+		switch baseType {
+		case "uint8":
+			value = uint8(v)
+		case "uint16":
+			value = uint16(v)
+		case "uint32":
+			value = uint32(v)
+		default:
+			value = v
+		}
+	case "fixed8", "byte", "bool":
+		value, err = decoder.ReadUint8()
+	case "fixed16":
+		value, err = decoder.ReadUint16()
+	case "fixed32":
+		value, err = decoder.ReadUint32()
+	case "fixed64":
+		value, err = decoder.ReadUint64()
+	case "float":
+		value, err = decoder.ReadFloat32()
+	case "double":
+		value, err = decoder.ReadFloat64()
+	case "string", "image":
+		value, err = abiutil.ReadString(decoder)
+	case "ipfs":
+		var hash string
+		hash, err = abiutil.ReadString(decoder)
+		value = base58.Encode([]byte(hash))
+	default:
+		err = fmt.Errorf("unknown format type: %v", baseType)
+	}
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(value), nil
+}
+
+func zigzagDecode(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+// GetImmutableData decodes the immutable serialized data using the format of the asset schema
+func (m *Asset) GetImmutableData(formats []*Format) (AttributeMap, error) {
+	return DeserializeData(m.ImmutableSerializedData, formats)
+}
+
+// GetMutableData decodes the mutable serialized data using the format of the asset schema
+func (m *Asset) GetMutableData(formats []*Format) (AttributeMap, error) {
+	return DeserializeData(m.MutableSerializedData, formats)
+}
+
+// GetImmutableData decodes the immutable serialized data using the format of the template schema
+func (m *Template) GetImmutableData(formats []*Format) (AttributeMap, error) {
+	return DeserializeData(m.ImmutableSerializedData, formats)
+}
+
+// GetData decodes the serialized data using the collection format
+func (m *Collection) GetData(formats []*Format) (AttributeMap, error) {
+	return DeserializeData(m.SerializedData, formats)
+}
+
+// SerializeData validates the attributes against the format and encodes them following the AtomicAssets rules,
+// attributes are written in format order
+func SerializeData(attributes AttributeMap, formats []*Format) ([]uint8, error) {
+	err := ValidateData(attributes, formats)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]uint8, 0)
+	for i, format := range formats {
+		attribute, ok := attributes[format.Name]
+		if !ok {
+			continue
+		}
+		data = appendUvarint(data, uint64(i+ReservedAttributeIDs))
+		data, err = serializeAttribute(data, format.Type, attribute)
+		if err != nil {
+			return nil, fmt.Errorf("failed serializing attribute: %v of type: %v, error: %v", format.Name, format.Type, err)
+		}
+	}
+	return data, nil
+}
+
+func serializeAttribute(data []uint8, formatType string, attribute *AtomicAttribute) ([]uint8, error) {
+	baseType, isVector := splitFormatType(formatType)
+	value := reflect.ValueOf(attribute.Impl)
+	if !isVector {
+		return serializeFormatValue(data, baseType, value)
+	}
+	data = appendUvarint(data, uint64(value.Len()))
+	var err error
+	for i := 0; i < value.Len(); i++ {
+		data, err = serializeFormatValue(data, baseType, value.Index(i))
+		if err != nil {
+			return nil, fmt.Errorf("element at position: %v, %v", i, err)
+		}
+	}
+	return data, nil
+}
+
+// serializeFormatValue expects a value of the type held by the format variant, see deserializeFormatValue
+// for the encoding rules
+func serializeFormatValue(data []uint8, baseType string, value reflect.Value) ([]uint8, error) {
+	switch baseType {
+	case "int8", "int16", "int32", "int64":
+		return appendUvarint(data, zigzagEncode(value.Int())), nil
+	case "uint8", "uint16", "uint32", "uint64":
+		return appendUvarint(data, value.Uint()), nil
+	case "fixed8", "byte", "bool":
+		return append(data, uint8(value.Uint())), nil
+	case "fixed16":
+		return appendLittleEndian(data, value.Uint(), 2), nil
+	case "fixed32":
+		return appendLittleEndian(data, value.Uint(), 4), nil
+	case "fixed64":
+		return appendLittleEndian(data, value.Uint(), 8), nil
+	case "float":
+		return appendLittleEndian(data, uint64(math.Float32bits(float32(value.Float()))), 4), nil
+	case "double":
+		return appendLittleEndian(data, math.Float64bits(value.Float()), 8), nil
+	case "string", "image":
+		return appendBytes(data, []byte(value.String())), nil
+	case "ipfs":
+		hash, err := decodeIPFSHash(value.String())
+		if err != nil {
+			return nil, err
+		}
+		return appendBytes(data, hash), nil
+	default:
+		return nil, fmt.Errorf("unknown format type: %v", baseType)
+	}
+}
+
+func zigzagEncode(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+func appendUvarint(data []uint8, v uint64) []uint8 {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return append(data, buf[:binary.PutUvarint(buf, v)]...)
+}
+
+func appendLittleEndian(data []uint8, v uint64, size int) []uint8 {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, v)
+	return append(data, buf[:size]...)
+}
+
+func appendBytes(data []uint8, b []byte) []uint8 {
+	return append(appendUvarint(data, uint64(len(b))), b...)
+}
+
+// decodeIPFSHash base58 decodes the hash, the decoding is verified by encoding it back as the base58 decoder
+// ignores invalid characters
+func decodeIPFSHash(hash string) ([]byte, error) {
+	decoded := base58.Decode(hash)
+	if hash == "" || base58.Encode(decoded) != hash {
+		return nil, fmt.Errorf("invalid ipfs hash: %q", hash)
+	}
+	return decoded, nil
+}
+
+// ValidateData checks that every attribute is in the format, that its type is the one used by AtomicAssets
+// for the format type and that its value is in range, e.g. bool values must be 0 or 1
+func ValidateData(attributes AttributeMap, formats []*Format) error {
+	formatTypes := make(map[string]string, len(formats))
+	for _, format := range formats {
+		formatTypes[format.Name] = format.Type
+	}
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		formatType, ok := formatTypes[key]
+		if !ok {
+			return fmt.Errorf("attribute: %v is not in the format", key)
+		}
+		err := validateAttribute(formatType, attributes[key])
+		if err != nil {
+			return fmt.Errorf("invalid attribute: %v, error: %v", key, err)
+		}
+	}
+	return nil
+}
+
+func validateAttribute(formatType string, attribute *AtomicAttribute) error {
+	variant, err := FormatVariant(formatType)
+	if err != nil {
+		return err
+	}
+	if attribute == nil || attribute.BaseVariant == nil {
+		return fmt.Errorf("attribute has no value")
+	}
+	expectedType := reflect.TypeOf(atomicAttributeTypes[AtomicAttributeVariant.TypeID(variant)].Type)
+	if reflect.TypeOf(attribute.Impl) != expectedType {
+		return fmt.Errorf("format type: %v expects a value of type: %v, found: %T", formatType, expectedType, attribute.Impl)
+	}
+	if int(attribute.TypeID) >= len(atomicAttributeTypes) || atomicAttributeTypes[attribute.TypeID].Name != variant {
+		return fmt.Errorf("format type: %v expects variant: %v, found type id: %v", formatType, variant, attribute.TypeID)
+	}
+	baseType, isVector := splitFormatType(formatType)
+	value := reflect.ValueOf(attribute.Impl)
+	if !isVector {
+		return validateFormatValue(baseType, value)
+	}
+	for i := 0; i < value.Len(); i++ {
+		if err := validateFormatValue(baseType, value.Index(i)); err != nil {
+			return fmt.Errorf("element at position: %v, %v", i, err)
+		}
+	}
+	return nil
+}
+
+func validateFormatValue(baseType string, value reflect.Value) error {
+	switch baseType {
+	case "bool":
+		if value.Uint() > 1 {
+			return fmt.Errorf("bool value: %v out of range, expected 0 or 1", value.Uint())
+		}
+	case "ipfs":
+		_, err := decodeIPFSHash(value.String())
+		return err
+	}
+	return nil
+}
+
+// CoerceData converts numeric attributes to the types expected by the format, returning an error if a value
+// is out of range for the expected type, i.e. an int64 can be used for a uint32 attribute if it is between 0
+// and math.MaxUint32. The resulting attributes are validated against the format
+func CoerceData(attributes AttributeMap, formats []*Format) (AttributeMap, error) {
+	formatTypes := make(map[string]string, len(formats))
+	for _, format := range formats {
+		formatTypes[format.Name] = format.Type
+	}
+	coerced := make(AttributeMap, len(attributes))
+	for key, attribute := range attributes {
+		formatType, ok := formatTypes[key]
+		if !ok {
+			return nil, fmt.Errorf("attribute: %v is not in the format", key)
+		}
+		if attribute == nil || attribute.BaseVariant == nil {
+			return nil, fmt.Errorf("invalid attribute: %v, error: attribute has no value", key)
+		}
+		variant, err := FormatVariant(formatType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute: %v, error: %v", key, err)
+		}
+		expectedType := reflect.TypeOf(atomicAttributeTypes[AtomicAttributeVariant.TypeID(variant)].Type)
+		value, err := coerceValue(reflect.ValueOf(attribute.Impl), expectedType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute: %v, error: %v", key, err)
+		}
+		coerced[key] = NewAtomicAttribute(variant, value.Interface())
+	}
+	err := ValidateData(coerced, formats)
+	if err != nil {
+		return nil, err
+	}
+	return coerced, nil
+}
+
+func coerceValue(value reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if !value.IsValid() {
+		return reflect.Value{}, fmt.Errorf("attribute has no value")
+	}
+	if value.Type() == t {
+		return value, nil
+	}
+	out := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i = value.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if value.Uint() > math.MaxInt64 {
+				return reflect.Value{}, fmt.Errorf("value: %v out of range for type: %v", value.Uint(), t)
+			}
+			i = int64(value.Uint())
+		case reflect.Float32, reflect.Float64:
+			f := value.Float()
+			if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+				return reflect.Value{}, fmt.Errorf("value: %v can not be converted to type: %v", f, t)
+			}
+			i = int64(f)
+		default:
+			return reflect.Value{}, fmt.Errorf("value of type: %v can not be converted to type: %v", value.Type(), t)
+		}
+		if out.OverflowInt(i) {
+			return reflect.Value{}, fmt.Errorf("value: %v out of range for type: %v", i, t)
+		}
+		out.SetInt(i)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if value.Int() < 0 {
+				return reflect.Value{}, fmt.Errorf("value: %v out of range for type: %v", value.Int(), t)
+			}
+			u = uint64(value.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			u = value.Uint()
+		case reflect.Float32, reflect.Float64:
+			f := value.Float()
+			if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+				return reflect.Value{}, fmt.Errorf("value: %v can not be converted to type: %v", f, t)
+			}
+			u = uint64(f)
+		case reflect.Bool:
+			if value.Bool() {
+				u = 1
+			}
+		default:
+			return reflect.Value{}, fmt.Errorf("value of type: %v can not be converted to type: %v", value.Type(), t)
+		}
+		if out.OverflowUint(u) {
+			return reflect.Value{}, fmt.Errorf("value: %v out of range for type: %v", u, t)
+		}
+		out.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			f = float64(value.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			f = float64(value.Uint())
+		case reflect.Float32, reflect.Float64:
+			f = value.Float()
+		default:
+			return reflect.Value{}, fmt.Errorf("value of type: %v can not be converted to type: %v", value.Type(), t)
+		}
+		if out.OverflowFloat(f) {
+			return reflect.Value{}, fmt.Errorf("value: %v out of range for type: %v", f, t)
+		}
+		out.SetFloat(f)
+	case reflect.Slice:
+		if value.Kind() != reflect.Slice {
+			return reflect.Value{}, fmt.Errorf("value of type: %v can not be converted to type: %v", value.Type(), t)
+		}
+		out = reflect.MakeSlice(t, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			element, err := coerceValue(value.Index(i), t.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element at position: %v, %v", i, err)
+			}
+			out = reflect.Append(out, element)
+		}
+	default:
+		return reflect.Value{}, fmt.Errorf("value of type: %v can not be converted to type: %v", value.Type(), t)
+	}
+	return out, nil
+}
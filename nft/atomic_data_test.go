@@ -0,0 +1,152 @@
+package nft_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/eoscanada/eos-go/btcsuite/btcutil/base58"
+	"github.com/sebastianmontero/bennyfi-go-client/nft"
+	"gotest.tools/assert"
+)
+
+const ipfsHash = "QmWATWQ7fVPP2EFGu71UkfnqhYXDYH566qy47CnJDgvs8u"
+
+var testFormats = []*nft.Format{
+	{Name: "name", Type: "string"},
+	{Name: "img", Type: "ipfs"},
+	{Name: "level", Type: "uint32"},
+	{Name: "score", Type: "int16"},
+	{Name: "rarity", Type: "fixed8"},
+	{Name: "ratio", Type: "float"},
+	{Name: "tags", Type: "string[]"},
+	{Name: "active", Type: "bool"},
+	{Name: "offsets", Type: "int64[]"},
+}
+
+func testSerializedData(t *testing.T) []byte {
+	data, err := hex.DecodeString("040261620522" + hex.EncodeToString(base58.Decode(ipfsHash)) +
+		"06ac020703080509" + "0000c03f" + "0a0201780279" + "7a" + "0b01" + "0c020102")
+	assert.NilError(t, err)
+	return data
+}
+
+func TestDeserializeData(t *testing.T) {
+	attributes, err := nft.DeserializeData(testSerializedData(t), testFormats)
+	assert.NilError(t, err)
+	assert.Equal(t, len(attributes), 9)
+	assert.Assert(t, attributes["name"].IsEqual(nft.NewAtomicAttribute("string", "ab")))
+	assert.Assert(t, attributes["img"].IsEqual(nft.NewAtomicAttribute("string", ipfsHash)))
+	assert.Assert(t, attributes["level"].IsEqual(nft.NewAtomicAttribute("uint32", uint32(300))))
+	assert.Assert(t, attributes["score"].IsEqual(nft.NewAtomicAttribute("int16", int16(-2))))
+	assert.Assert(t, attributes["rarity"].IsEqual(nft.NewAtomicAttribute("uint8", uint8(5))))
+	assert.Assert(t, attributes["ratio"].IsEqual(nft.NewAtomicAttribute("float", float32(1.5))))
+	assert.Assert(t, attributes["tags"].IsEqual(nft.NewAtomicAttribute("STRING_VEC", []string{"x", "yz"})))
+	assert.Assert(t, attributes["active"].IsEqual(nft.NewAtomicAttribute("uint8", uint8(1))))
+	assert.Assert(t, attributes["offsets"].IsEqual(nft.NewAtomicAttribute("INT64_VEC", []int64{-1, 1})))
+
+	asset := &nft.Asset{ImmutableSerializedData: []uint8{0x04, 0x01, 0x61}, MutableSerializedData: []uint8{}}
+	immutable, err := asset.GetImmutableData(testFormats)
+	assert.NilError(t, err)
+	assert.Equal(t, immutable["name"].String(), "a")
+	mutable, err := asset.GetMutableData(testFormats)
+	assert.NilError(t, err)
+	assert.Equal(t, len(mutable), 0)
+}
+
+func TestDeserializeDataErrors(t *testing.T) {
+	_, err := nft.DeserializeData([]byte{0x03, 0x01}, testFormats)
+	assert.ErrorContains(t, err, "attribute identifier: 3 is not in the format")
+	_, err = nft.DeserializeData([]byte{0x0d, 0x01}, testFormats)
+	assert.ErrorContains(t, err, "attribute identifier: 13 is not in the format")
+	_, err = nft.DeserializeData([]byte{0x04, 0x05, 0x61}, testFormats)
+	assert.ErrorContains(t, err, "unable to read attribute: name of type: string")
+	_, err = nft.DeserializeData([]byte{0x04, 0x01, 0x61}, []*nft.Format{{Name: "name", Type: "text"}})
+	assert.ErrorContains(t, err, "unknown format type: text")
+
+	variant, err := nft.FormatVariant("image[]")
+	assert.NilError(t, err)
+	assert.Equal(t, variant, "STRING_VEC")
+	_, err = nft.FormatVariant("int8[][]")
+	assert.ErrorContains(t, err, "unknown format type")
+}
+
+func testAttributes() nft.AttributeMap {
+	return nft.AttributeMap{
+		"name":    nft.NewAtomicAttribute("string", "ab"),
+		"img":     nft.NewAtomicAttribute("string", ipfsHash),
+		"level":   nft.NewAtomicAttribute("uint32", uint32(300)),
+		"score":   nft.NewAtomicAttribute("int16", int16(-2)),
+		"rarity":  nft.NewAtomicAttribute("uint8", uint8(5)),
+		"ratio":   nft.NewAtomicAttribute("float", float32(1.5)),
+		"tags":    nft.NewAtomicAttribute("STRING_VEC", []string{"x", "yz"}),
+		"active":  nft.NewAtomicAttribute("uint8", uint8(1)),
+		"offsets": nft.NewAtomicAttribute("INT64_VEC", []int64{-1, 1}),
+	}
+}
+
+func TestSerializeData(t *testing.T) {
+	data, err := nft.SerializeData(testAttributes(), testFormats)
+	assert.NilError(t, err)
+	assert.Equal(t, hex.EncodeToString(data), hex.EncodeToString(testSerializedData(t)))
+
+	data, err = nft.SerializeData(nft.AttributeMap{
+		"a": nft.NewAtomicAttribute("uint16", uint16(0x0102)),
+		"b": nft.NewAtomicAttribute("double", float64(-2)),
+		"c": nft.NewAtomicAttribute("int64", int64(-9223372036854775808)),
+	}, []*nft.Format{{Name: "a", Type: "fixed16"}, {Name: "b", Type: "double"}, {Name: "c", Type: "int64"}})
+	assert.NilError(t, err)
+	assert.Equal(t, hex.EncodeToString(data), "040201"+"05"+"00000000000000c0"+"06"+"ffffffffffffffffff01")
+
+	attributes, err := nft.DeserializeData(data, []*nft.Format{{Name: "a", Type: "fixed16"}, {Name: "b", Type: "double"}, {Name: "c", Type: "int64"}})
+	assert.NilError(t, err)
+	assert.Equal(t, attributes["c"].String(), "-9223372036854775808")
+}
+
+func TestValidateData(t *testing.T) {
+	assert.NilError(t, nft.ValidateData(testAttributes(), testFormats))
+
+	attributes := testAttributes()
+	attributes["unknown"] = nft.NewAtomicAttribute("string", "x")
+	assert.ErrorContains(t, nft.ValidateData(attributes, testFormats), "attribute: unknown is not in the format")
+
+	attributes = testAttributes()
+	attributes["level"] = nft.NewAtomicAttribute("int64", int64(300))
+	assert.ErrorContains(t, nft.ValidateData(attributes, testFormats), "format type: uint32 expects a value of type: uint32, found: int64")
+
+	attributes = testAttributes()
+	attributes["active"] = nft.NewAtomicAttribute("uint8", uint8(2))
+	assert.ErrorContains(t, nft.ValidateData(attributes, testFormats), "bool value: 2 out of range")
+
+	attributes = testAttributes()
+	attributes["img"] = nft.NewAtomicAttribute("string", "not-base58!")
+	_, err := nft.SerializeData(attributes, testFormats)
+	assert.ErrorContains(t, err, "invalid ipfs hash")
+}
+
+func TestCoerceData(t *testing.T) {
+	attributes := nft.AttributeMap{
+		"level":   nft.NewAtomicAttribute("int64", int64(300)),
+		"score":   nft.NewAtomicAttribute("double", float64(-2)),
+		"ratio":   nft.NewAtomicAttribute("int64", int64(3)),
+		"offsets": nft.NewAtomicAttribute("INT32_VEC", []int32{-1, 1}),
+		"name":    nft.NewAtomicAttribute("string", "ab"),
+	}
+	coerced, err := nft.CoerceData(attributes, testFormats)
+	assert.NilError(t, err)
+	assert.Assert(t, coerced["level"].IsEqual(nft.NewAtomicAttribute("uint32", uint32(300))))
+	assert.Assert(t, coerced["score"].IsEqual(nft.NewAtomicAttribute("int16", int16(-2))))
+	assert.Assert(t, coerced["ratio"].IsEqual(nft.NewAtomicAttribute("float", float32(3))))
+	assert.Assert(t, coerced["offsets"].IsEqual(nft.NewAtomicAttribute("INT64_VEC", []int64{-1, 1})))
+	assert.Assert(t, attributes["level"].IsEqual(nft.NewAtomicAttribute("int64", int64(300))))
+
+	_, err = nft.CoerceData(nft.AttributeMap{"rarity": nft.NewAtomicAttribute("int64", int64(256))}, testFormats)
+	assert.ErrorContains(t, err, "value: 256 out of range for type: uint8")
+	_, err = nft.CoerceData(nft.AttributeMap{"level": nft.NewAtomicAttribute("int64", int64(-1))}, testFormats)
+	assert.ErrorContains(t, err, "value: -1 out of range for type: uint32")
+	_, err = nft.CoerceData(nft.AttributeMap{"score": nft.NewAtomicAttribute("double", 1.5)}, testFormats)
+	assert.ErrorContains(t, err, "value: 1.5 can not be converted to type: int16")
+	_, err = nft.CoerceData(nft.AttributeMap{"name": nft.NewAtomicAttribute("int64", int64(1))}, testFormats)
+	assert.ErrorContains(t, err, "value of type: int64 can not be converted to type: string")
+	_, err = nft.CoerceData(nft.AttributeMap{"active": nft.NewAtomicAttribute("int64", int64(2))}, testFormats)
+	assert.ErrorContains(t, err, "bool value: 2 out of range")
+}
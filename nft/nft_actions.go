@@ -0,0 +1,262 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package nft
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/eoscanada/eos-go"
+)
+
+// The action args are encoded by field order, fields tagged with eos:"-" are only used to authorize the action
+
+type TransferArgs struct {
+	From     eos.AccountName `json:"from"`
+	To       eos.AccountName `json:"to"`
+	AssetIds []uint64        `json:"asset_ids"`
+	Memo     string          `json:"memo"`
+}
+
+type BurnAssetArgs struct {
+	AssetOwner eos.AccountName `json:"asset_owner"`
+	AssetId    uint64          `json:"asset_id"`
+}
+
+type SetAssetDataArgs struct {
+	AuthorizedEditor eos.AccountName `json:"authorized_editor"`
+	AssetOwner       eos.AccountName `json:"asset_owner"`
+	AssetId          uint64          `json:"asset_id"`
+	NewMutableData   AttributeMap    `json:"new_mutable_data"`
+}
+
+// CollectionAccountArgs is used by the addcolauth, remcolauth, addnotifyacc and remnotifyacc actions, which
+// must be authorized by the collection author, Account is the account_to_add or account_to_remove field
+type CollectionAccountArgs struct {
+	Author         eos.AccountName `json:"-" eos:"-"`
+	CollectionName eos.Name        `json:"collection_name"`
+	Account        eos.AccountName `json:"account"`
+}
+
+type SetMarketFeeArgs struct {
+	Author         eos.AccountName `json:"-" eos:"-"`
+	CollectionName eos.Name        `json:"collection_name"`
+	MarketFee      float64         `json:"market_fee"`
+}
+
+type ExtendSchemaArgs struct {
+	AuthorizedEditor      eos.AccountName `json:"authorized_editor"`
+	CollectionName        eos.Name        `json:"collection_name"`
+	SchemaName            eos.Name        `json:"schema_name"`
+	SchemaFormatExtension []*Format       `json:"schema_format_extension"`
+}
+
+type LockTemplateArgs struct {
+	AuthorizedEditor eos.AccountName `json:"authorized_editor"`
+	CollectionName   eos.Name        `json:"collection_name"`
+	TemplateId       int32           `json:"template_id"`
+}
+
+type AnnounceDepositArgs struct {
+	Owner            eos.AccountName `json:"owner"`
+	SymbolToAnnounce eos.Symbol      `json:"symbol_to_announce"`
+}
+
+// BackAssetArgs backs the asset with tokens previously deposited by the payer, see AnnounceDeposit
+type BackAssetArgs struct {
+	Payer       eos.AccountName `json:"payer"`
+	AssetOwner  eos.AccountName `json:"asset_owner"`
+	AssetId     uint64          `json:"asset_id"`
+	TokenToBack eos.Asset       `json:"token_to_back"`
+}
+
+type CreateOfferArgs struct {
+	Sender            eos.AccountName `json:"sender"`
+	Recipient         eos.AccountName `json:"recipient"`
+	SenderAssetIds    []uint64        `json:"sender_asset_ids"`
+	RecipientAssetIds []uint64        `json:"recipient_asset_ids"`
+	Memo              string          `json:"memo"`
+}
+
+// OfferArgs is used by the acceptoffer and declineoffer actions, authorized by the recipient, and by the
+// canceloffer action, authorized by the sender
+type OfferArgs struct {
+	Authorizer eos.AccountName `json:"-" eos:"-"`
+	OfferId    uint64          `json:"offer_id"`
+}
+
+type Offer struct {
+	OfferId           eos.Uint64      `json:"offer_id"`
+	Sender            eos.AccountName `json:"sender"`
+	Recipient         eos.AccountName `json:"recipient"`
+	SenderAssetIds    []string        `json:"sender_asset_ids"`
+	RecipientAssetIds []string        `json:"recipient_asset_ids"`
+	Memo              string          `json:"memo"`
+	RamPayer          eos.AccountName `json:"ram_payer"`
+}
+
+func (m *NFTContract) Transfer(args *TransferArgs) (string, error) {
+	return m.ExecAction(args.From, "transfer", args)
+}
+
+func (m *NFTContract) BurnAsset(args *BurnAssetArgs) (string, error) {
+	return m.ExecAction(args.AssetOwner, "burnasset", args)
+}
+
+// SetAssetData validates the new mutable data against the asset schema before setting it
+func (m *NFTContract) SetAssetData(args *SetAssetDataArgs) (string, error) {
+	asset, err := m.GetAssetById(args.AssetOwner, args.AssetId)
+	if err != nil {
+		return "", err
+	}
+	if asset == nil {
+		return "", fmt.Errorf("asset: %v owned by: %v not found", args.AssetId, args.AssetOwner)
+	}
+	formats, err := m.getSchemaFormat(asset.CollectionName, asset.SchemaName)
+	if err != nil {
+		return "", err
+	}
+	err = ValidateData(args.NewMutableData, formats)
+	if err != nil {
+		return "", fmt.Errorf("invalid asset mutable data, error: %v", err)
+	}
+	return m.ExecAction(args.AuthorizedEditor, "setassetdata", args)
+}
+
+func (m *NFTContract) AddCollectionAuth(args *CollectionAccountArgs) (string, error) {
+	return m.ExecAction(args.Author, "addcolauth", args)
+}
+
+func (m *NFTContract) RemoveCollectionAuth(args *CollectionAccountArgs) (string, error) {
+	return m.ExecAction(args.Author, "remcolauth", args)
+}
+
+func (m *NFTContract) AddNotifyAccount(args *CollectionAccountArgs) (string, error) {
+	return m.ExecAction(args.Author, "addnotifyacc", args)
+}
+
+func (m *NFTContract) RemoveNotifyAccount(args *CollectionAccountArgs) (string, error) {
+	return m.ExecAction(args.Author, "remnotifyacc", args)
+}
+
+func (m *NFTContract) SetMarketFee(args *SetMarketFeeArgs) (string, error) {
+	return m.ExecAction(args.Author, "setmarketfee", args)
+}
+
+// ExtendSchema checks that the extension does not redefine attributes of the schema and that its types are
+// supported before extending the schema
+func (m *NFTContract) ExtendSchema(args *ExtendSchemaArgs) (string, error) {
+	formats, err := m.getSchemaFormat(args.CollectionName, args.SchemaName)
+	if err != nil {
+		return "", err
+	}
+	err = ValidateFormats(append(append([]*Format{}, formats...), args.SchemaFormatExtension...))
+	if err != nil {
+		return "", fmt.Errorf("invalid schema format extension, error: %v", err)
+	}
+	return m.ExecAction(args.AuthorizedEditor, "extendschema", args)
+}
+
+func (m *NFTContract) LockTemplate(args *LockTemplateArgs) (string, error) {
+	return m.ExecAction(args.AuthorizedEditor, "locktemplate", args)
+}
+
+// AnnounceDeposit must be called before transferring the tokens used to back assets to the contract
+func (m *NFTContract) AnnounceDeposit(args *AnnounceDepositArgs) (string, error) {
+	return m.ExecAction(args.Owner, "announcedepo", args)
+}
+
+func (m *NFTContract) BackAsset(args *BackAssetArgs) (string, error) {
+	return m.ExecAction(args.Payer, "backasset", args)
+}
+
+func (m *NFTContract) CreateOffer(args *CreateOfferArgs) (string, error) {
+	return m.ExecAction(args.Sender, "createoffer", args)
+}
+
+func (m *NFTContract) AcceptOffer(args *OfferArgs) (string, error) {
+	return m.ExecAction(args.Authorizer, "acceptoffer", args)
+}
+
+func (m *NFTContract) DeclineOffer(args *OfferArgs) (string, error) {
+	return m.ExecAction(args.Authorizer, "declineoffer", args)
+}
+
+func (m *NFTContract) CancelOffer(args *OfferArgs) (string, error) {
+	return m.ExecAction(args.Authorizer, "canceloffer", args)
+}
+
+// ValidateFormats checks that the attribute names are unique and that the types are supported by AtomicAssets
+func ValidateFormats(formats []*Format) error {
+	names := make(map[string]bool, len(formats))
+	for _, format := range formats {
+		if format.Name == "" {
+			return fmt.Errorf("format attributes must have a name")
+		}
+		if names[format.Name] {
+			return fmt.Errorf("attribute: %v is defined more than once", format.Name)
+		}
+		names[format.Name] = true
+		if _, err := FormatVariant(format.Type); err != nil {
+			return fmt.Errorf("attribute: %v, %v", format.Name, err)
+		}
+	}
+	return nil
+}
+
+func (m *NFTContract) GetOffer(offerId uint64) (*Offer, error) {
+	offers, err := m.GetOffersReq(&eos.GetTableRowsRequest{
+		LowerBound: strconv.FormatUint(offerId, 10),
+		UpperBound: strconv.FormatUint(offerId, 10),
+		Limit:      1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(offers) > 0 {
+		return &offers[0], nil
+	}
+	return nil, nil
+}
+
+func (m *NFTContract) GetOffersByRecipient(recipient eos.AccountName) ([]Offer, error) {
+	return m.GetOffersReq(&eos.GetTableRowsRequest{
+		Index:      "3",
+		KeyType:    "name",
+		LowerBound: string(recipient),
+		UpperBound: string(recipient),
+	})
+}
+
+func (m *NFTContract) GetOffersReq(req *eos.GetTableRowsRequest) ([]Offer, error) {
+
+	var offers []Offer
+	if req == nil {
+		req = &eos.GetTableRowsRequest{}
+	}
+	req.Table = "offers"
+	err := m.GetTableRows(*req, &offers)
+	if err != nil {
+		return nil, fmt.Errorf("get table rows %v", err)
+	}
+	return offers, nil
+}
@@ -0,0 +1,50 @@
+package nft_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/nft"
+	"gotest.tools/assert"
+)
+
+func encodeArgs(t *testing.T, args interface{}) string {
+	data, err := eos.MarshalBinary(args)
+	assert.NilError(t, err)
+	return hex.EncodeToString(data)
+}
+
+func nameHex(t *testing.T, name string) string {
+	return encodeArgs(t, eos.Name(name))
+}
+
+func TestActionArgsEncoding(t *testing.T) {
+	assert.Equal(t, encodeArgs(t, &nft.OfferArgs{Authorizer: "alice", OfferId: 5}), "0500000000000000")
+	assert.Equal(t, encodeArgs(t, &nft.CollectionAccountArgs{Author: "alice", CollectionName: "bennyfi", Account: "bob"}),
+		nameHex(t, "bennyfi")+nameHex(t, "bob"))
+	assert.Equal(t, encodeArgs(t, &nft.SetMarketFeeArgs{Author: "alice", CollectionName: "bennyfi", MarketFee: 0.5}),
+		nameHex(t, "bennyfi")+"000000000000e03f")
+	assert.Equal(t, encodeArgs(t, &nft.TransferArgs{From: "alice", To: "bob", AssetIds: []uint64{1, 2}, Memo: "hi"}),
+		nameHex(t, "alice")+nameHex(t, "bob")+"02"+"0100000000000000"+"0200000000000000"+"026869")
+	assert.Equal(t, encodeArgs(t, &nft.LockTemplateArgs{AuthorizedEditor: "alice", CollectionName: "bennyfi", TemplateId: 3}),
+		nameHex(t, "alice")+nameHex(t, "bennyfi")+"03000000")
+	assert.Equal(t, encodeArgs(t, &nft.AnnounceDepositArgs{Owner: "alice", SymbolToAnnounce: eos.Symbol{Precision: 4, Symbol: "TLOS"}}),
+		nameHex(t, "alice")+"04544c4f53000000")
+}
+
+func TestOfferDecoding(t *testing.T) {
+	var offers []nft.Offer
+	err := json.Unmarshal([]byte(`[{"offer_id":"1099511627776","sender":"alice"},{"offer_id":5,"sender":"bob"}]`), &offers)
+	assert.NilError(t, err)
+	assert.Equal(t, offers[0].OfferId, eos.Uint64(1099511627776))
+	assert.Equal(t, offers[1].OfferId, eos.Uint64(5))
+}
+
+func TestValidateFormats(t *testing.T) {
+	assert.NilError(t, nft.ValidateFormats(testFormats))
+	assert.ErrorContains(t, nft.ValidateFormats(append(testFormats, &nft.Format{Name: "name", Type: "string"})), "attribute: name is defined more than once")
+	assert.ErrorContains(t, nft.ValidateFormats([]*nft.Format{{Name: "x", Type: "text"}}), "attribute: x, unknown format type: text")
+	assert.ErrorContains(t, nft.ValidateFormats([]*nft.Format{{Type: "string"}}), "must have a name")
+}
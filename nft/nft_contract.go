@@ -35,6 +35,20 @@ type Format struct {
 	Type string `json:"type"`
 }
 
+// Config is the config singleton of the atomicassets contract
+type Config struct {
+	AssetCounter     eos.Uint64       `json:"asset_counter"`
+	TemplateCounter  int32            `json:"template_counter"`
+	OfferCounter     eos.Uint64       `json:"offer_counter"`
+	CollectionFormat []*Format        `json:"collection_format"`
+	SupportedTokens  []ExtendedSymbol `json:"supported_tokens"`
+}
+
+type ExtendedSymbol struct {
+	Symbol   string          `json:"sym"`
+	Contract eos.AccountName `json:"contract"`
+}
+
 type BaseCollection struct {
 	Author             eos.AccountName   `json:"author"`
 	CollectionName     eos.Name          `json:"collection_name"`
@@ -209,14 +223,63 @@ func (m *NFTContract) CreateSchema(schema *CreateSchemaArgs) (string, error) {
 	return m.ExecAction(schema.AuthorizedCreator, "createschema", schema)
 }
 
+// CreateTemplate validates the immutable data against the schema before creating the template
 func (m *NFTContract) CreateTemplate(template *CreateTemplateArgs) (string, error) {
+	err := m.ValidateCreateTemplate(template)
+	if err != nil {
+		return "", err
+	}
 	return m.ExecAction(template.AuthorizedCreator, "createtempl", template)
 }
 
+// MintAsset validates the immutable and mutable data against the schema before minting the asset
 func (m *NFTContract) MintAsset(asset *MintAssetArgs) (string, error) {
+	err := m.ValidateMintAsset(asset)
+	if err != nil {
+		return "", err
+	}
 	return m.ExecAction(asset.AuthorizedMinter, "mintasset", asset)
 }
 
+func (m *NFTContract) ValidateCreateTemplate(template *CreateTemplateArgs) error {
+	formats, err := m.getSchemaFormat(template.CollectionName, template.SchemaName)
+	if err != nil {
+		return err
+	}
+	err = ValidateData(template.ImmutableData, formats)
+	if err != nil {
+		return fmt.Errorf("invalid template immutable data, error: %v", err)
+	}
+	return nil
+}
+
+func (m *NFTContract) ValidateMintAsset(asset *MintAssetArgs) error {
+	formats, err := m.getSchemaFormat(asset.CollectionName, asset.SchemaName)
+	if err != nil {
+		return err
+	}
+	err = ValidateData(asset.ImmutableData, formats)
+	if err != nil {
+		return fmt.Errorf("invalid asset immutable data, error: %v", err)
+	}
+	err = ValidateData(asset.MutableData, formats)
+	if err != nil {
+		return fmt.Errorf("invalid asset mutable data, error: %v", err)
+	}
+	return nil
+}
+
+func (m *NFTContract) getSchemaFormat(collection, schemaName eos.Name) ([]*Format, error) {
+	schema, err := m.GetSchemaByName(collection, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting schema: %v of collection: %v, error: %v", schemaName, collection, err)
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("schema: %v of collection: %v not found", schemaName, collection)
+	}
+	return schema.Format, nil
+}
+
 func (m *NFTContract) EditCollectionFormats(formats []*Format) (string, error) {
 	actionData := make(map[string]interface{})
 	actionData["collection_format_extension"] = formats
@@ -364,3 +427,47 @@ func (m *NFTContract) GetSchemasReq(collectionName eos.Name, req *eos.GetTableRo
 	}
 	return schemas, nil
 }
+
+func (m *NFTContract) GetConfig() (*Config, error) {
+	var config []Config
+	req := &eos.GetTableRowsRequest{
+		Table: "config",
+	}
+	err := m.GetTableRows(*req, &config)
+	if err != nil {
+		return nil, fmt.Errorf("get table rows %v", err)
+	}
+	if len(config) > 0 {
+		return &config[0], nil
+	}
+	return nil, nil
+}
+
+// GetCollectionFormat returns the format used to serialize the collection data
+func (m *NFTContract) GetCollectionFormat() ([]*Format, error) {
+	config, err := m.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, fmt.Errorf("nft contract has not been initialized")
+	}
+	return config.CollectionFormat, nil
+}
+
+// GetAssetData decodes the immutable and mutable data of the asset using the format of its schema
+func (m *NFTContract) GetAssetData(asset *Asset) (AttributeMap, AttributeMap, error) {
+	formats, err := m.getSchemaFormat(asset.CollectionName, asset.SchemaName)
+	if err != nil {
+		return nil, nil, err
+	}
+	immutableData, err := asset.GetImmutableData(formats)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed decoding immutable data of asset: %v, error: %v", asset.AssetId, err)
+	}
+	mutableData, err := asset.GetMutableData(formats)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed decoding mutable data of asset: %v, error: %v", asset.AssetId, err)
+	}
+	return immutableData, mutableData, nil
+}
@@ -0,0 +1,246 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package nft
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/internal/tablepage"
+)
+
+// AssetFilter selects the assets found by the asset scanner, empty fields match any asset. TemplateId 0 matches
+// any template, -1 matches the assets without template
+type AssetFilter struct {
+	CollectionName eos.Name
+	SchemaName     eos.Name
+	TemplateId     int32
+}
+
+func (m *AssetFilter) Matches(asset *Asset) bool {
+	if asset.BaseAsset == nil {
+		return false
+	}
+	if m.CollectionName != "" && asset.CollectionName != m.CollectionName {
+		return false
+	}
+	if m.SchemaName != "" && asset.SchemaName != m.SchemaName {
+		return false
+	}
+	return m.TemplateId == 0 || asset.TemplateId == m.TemplateId
+}
+
+type tableScope struct {
+	Scope eos.AccountName `json:"scope"`
+	Count uint32          `json:"count"`
+}
+
+func (m *NFTContract) GetTemplateById(collection eos.Name, templateId int32) (*Template, error) {
+	templates, err := m.GetTemplatesReq(collection, &eos.GetTableRowsRequest{
+		LowerBound: strconv.FormatInt(int64(templateId), 10),
+		UpperBound: strconv.FormatInt(int64(templateId), 10),
+		Limit:      1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) > 0 {
+		return &templates[0], nil
+	}
+	return nil, nil
+}
+
+// GetAllTemplates pages through the templates of the collection
+func (m *NFTContract) GetAllTemplates(collection eos.Name) ([]Template, error) {
+	templates := make([]Template, 0)
+	err := tablepage.ReadAll(func(lowerBound string) (int, bool, error) {
+		var page []Template
+		more, err := tablepage.GetTableRows(m.Contract, eos.GetTableRowsRequest{
+			Table:      "templates",
+			Scope:      string(collection),
+			LowerBound: lowerBound,
+		}, &page)
+		templates = append(templates, page...)
+		return len(page), more, err
+	}, func() (string, error) {
+		return tablepage.AfterID(uint64(templates[len(templates)-1].TemplateId)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// GetAllSchemas pages through the schemas of the collection, the lower bound of the next page is the
+// numeric value of the last schema name plus one
+func (m *NFTContract) GetAllSchemas(collection eos.Name) ([]Schema, error) {
+	schemas := make([]Schema, 0)
+	err := tablepage.ReadAll(func(lowerBound string) (int, bool, error) {
+		var page []Schema
+		more, err := tablepage.GetTableRows(m.Contract, eos.GetTableRowsRequest{
+			Table:      "schemas",
+			Scope:      string(collection),
+			LowerBound: lowerBound,
+		}, &page)
+		schemas = append(schemas, page...)
+		return len(page), more, err
+	}, func() (string, error) {
+		return tablepage.AfterName(string(schemas[len(schemas)-1].SchemaName)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schemas, nil
+}
+
+func (m *NFTContract) GetAssetById(owner eos.AccountName, assetId uint64) (*Asset, error) {
+	assets, err := m.GetAssetsReq(owner, &eos.GetTableRowsRequest{
+		LowerBound: strconv.FormatUint(assetId, 10),
+		UpperBound: strconv.FormatUint(assetId, 10),
+		Limit:      1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(assets) > 0 {
+		return &assets[0], nil
+	}
+	return nil, nil
+}
+
+// GetAllAssets pages through the assets of the owner, GetAssets only returns the first page
+func (m *NFTContract) GetAllAssets(owner eos.AccountName) ([]Asset, error) {
+	assets := make([]Asset, 0)
+	err := tablepage.ReadAll(func(lowerBound string) (int, bool, error) {
+		var page []Asset
+		more, err := tablepage.GetTableRows(m.Contract, eos.GetTableRowsRequest{
+			Table:      "assets",
+			Scope:      string(owner),
+			LowerBound: lowerBound,
+		}, &page)
+		assets = append(assets, page...)
+		return len(page), more, err
+	}, func() (string, error) {
+		lastId, err := strconv.ParseUint(assets[len(assets)-1].AssetId, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid asset id: %v, error: %v", assets[len(assets)-1].AssetId, err)
+		}
+		return tablepage.AfterID(lastId), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return assets, nil
+}
+
+// GetAssetOwners returns the accounts that have a scope with rows in the assets table
+func (m *NFTContract) GetAssetOwners() ([]eos.AccountName, error) {
+	owners := make([]eos.AccountName, 0)
+	lowerBound := ""
+	for {
+		resp, err := m.EOS.API.GetTableByScope(context.Background(), eos.GetTableByScopeRequest{
+			Code:       m.ContractName,
+			Table:      "assets",
+			LowerBound: lowerBound,
+			Limit:      tablepage.Size,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("get table by scope %v", err)
+		}
+		var scopes []tableScope
+		if len(resp.Rows) > 0 {
+			err = json.Unmarshal(resp.Rows, &scopes)
+			if err != nil {
+				return nil, fmt.Errorf("failed unmarshalling table scopes, error: %v", err)
+			}
+		}
+		for _, scope := range scopes {
+			if scope.Count > 0 {
+				owners = append(owners, scope.Scope)
+			}
+		}
+		if resp.More == "" {
+			return owners, nil
+		}
+		lowerBound = resp.More
+	}
+}
+
+// ScanAssets walks all the owner scopes of the assets table calling fn for each asset that matches the filter,
+// the scan stops at the first error returned by fn
+func (m *NFTContract) ScanAssets(filter *AssetFilter, fn func(owner eos.AccountName, asset *Asset) error) error {
+	if filter == nil {
+		filter = &AssetFilter{}
+	}
+	owners, err := m.GetAssetOwners()
+	if err != nil {
+		return fmt.Errorf("failed getting asset owners, error: %v", err)
+	}
+	for _, owner := range owners {
+		assets, err := m.GetAllAssets(owner)
+		if err != nil {
+			return fmt.Errorf("failed getting assets of owner: %v, error: %v", owner, err)
+		}
+		for i := range assets {
+			if !filter.Matches(&assets[i]) {
+				continue
+			}
+			if err := fn(owner, &assets[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// FindAssets returns the assets that match the filter grouped by owner
+func (m *NFTContract) FindAssets(filter *AssetFilter) (map[eos.AccountName][]Asset, error) {
+	found := make(map[eos.AccountName][]Asset)
+	err := m.ScanAssets(filter, func(owner eos.AccountName, asset *Asset) error {
+		found[owner] = append(found[owner], *asset)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// GetHolders returns the sorted accounts that own at least one asset that matches the filter
+func (m *NFTContract) GetHolders(filter *AssetFilter) ([]eos.AccountName, error) {
+	found, err := m.FindAssets(filter)
+	if err != nil {
+		return nil, err
+	}
+	holders := make([]eos.AccountName, 0, len(found))
+	for holder := range found {
+		holders = append(holders, holder)
+	}
+	sort.Slice(holders, func(i, j int) bool {
+		return holders[i] < holders[j]
+	})
+	return holders, nil
+}
@@ -0,0 +1,22 @@
+package nft_test
+
+import (
+	"testing"
+
+	"github.com/sebastianmontero/bennyfi-go-client/nft"
+	"gotest.tools/assert"
+)
+
+func TestAssetFilterMatches(t *testing.T) {
+	asset := &nft.Asset{BaseAsset: &nft.BaseAsset{CollectionName: "bennyfi", SchemaName: "badges", TemplateId: 3}}
+	assert.Assert(t, (&nft.AssetFilter{}).Matches(asset))
+	assert.Assert(t, (&nft.AssetFilter{CollectionName: "bennyfi"}).Matches(asset))
+	assert.Assert(t, (&nft.AssetFilter{CollectionName: "bennyfi", SchemaName: "badges", TemplateId: 3}).Matches(asset))
+	assert.Assert(t, !(&nft.AssetFilter{CollectionName: "other"}).Matches(asset))
+	assert.Assert(t, !(&nft.AssetFilter{SchemaName: "winners"}).Matches(asset))
+	assert.Assert(t, !(&nft.AssetFilter{TemplateId: 4}).Matches(asset))
+	assert.Assert(t, !(&nft.AssetFilter{TemplateId: -1}).Matches(asset))
+	asset.TemplateId = -1
+	assert.Assert(t, (&nft.AssetFilter{TemplateId: -1}).Matches(asset))
+	assert.Assert(t, !(&nft.AssetFilter{}).Matches(&nft.Asset{}))
+}
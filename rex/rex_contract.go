@@ -25,21 +25,11 @@ import (
 	"fmt"
 
 	"github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/chain"
 	"github.com/sebastianmontero/eos-go-toolbox/contract"
 	"github.com/sebastianmontero/eos-go-toolbox/service"
 )
 
-var (
-	RexSymbol = eos.Symbol{
-		Precision: 4,
-		Symbol:    "REX",
-	}
-	RexFundSymbol = eos.Symbol{
-		Precision: 4,
-		Symbol:    "TLOS",
-	}
-)
-
 type Config struct {
 	LendableIncrement uint64 `json:"lendable_increment"`
 }
@@ -59,17 +49,38 @@ type RexPool struct {
 	TotalRex      string `json:"total_rex"`
 }
 
+// RexClient is implemented by RexContract and by the in memory Simulator
+type RexClient interface {
+	Init(totalLendable, totalRex eos.Asset, lendableIncrement uint64) (string, error)
+	Deposit(owner eos.AccountName, amount eos.Asset) (string, error)
+	BuyRex(from eos.AccountName, amount eos.Asset) (string, error)
+	MoveToSavings(owner eos.AccountName, rex eos.Asset) (string, error)
+	MoveFromSavings(owner eos.AccountName, rex eos.Asset) (string, error)
+	SellRex(from eos.AccountName, rex eos.Asset) (string, error)
+	Withdraw(owner eos.AccountName, amount eos.Asset) (string, error)
+	GetConfig() (*Config, error)
+	GetBalance(owner eos.Name) (*Balance, error)
+	GetPool() (*RexPool, error)
+}
+
 type RexContract struct {
 	*contract.Contract
+	Chain chain.Chain
 }
 
-func NewRexContract(eos *service.EOS, contractName string) *RexContract {
+// NewRexContract creates a client for the rex contract of the chain, errors if the chain is not valid
+func NewRexContract(eos *service.EOS, chain chain.Chain) (*RexContract, error) {
+	err := chain.Validate()
+	if err != nil {
+		return nil, err
+	}
 	return &RexContract{
-		&contract.Contract{
+		Contract: &contract.Contract{
 			EOS:          eos,
-			ContractName: contractName,
+			ContractName: string(chain.RexContract),
 		},
-	}
+		Chain: chain,
+	}, nil
 }
 
 func (m *RexContract) ExecAction(permissionLevel interface{}, action string, actionData interface{}) (string, error) {
@@ -81,6 +92,12 @@ func (m *RexContract) ExecAction(permissionLevel interface{}, action string, act
 }
 
 func (m *RexContract) Init(totalLendable, totalRex eos.Asset, lendableIncrement uint64) (string, error) {
+	if err := m.Chain.CheckCoreAsset(totalLendable); err != nil {
+		return "", err
+	}
+	if err := m.Chain.CheckRexAsset(totalRex); err != nil {
+		return "", err
+	}
 	actionData := make(map[string]interface{})
 	actionData["total_lendable"] = totalLendable
 	actionData["total_rex"] = totalRex
@@ -90,6 +107,9 @@ func (m *RexContract) Init(totalLendable, totalRex eos.Asset, lendableIncrement
 }
 
 func (m *RexContract) Deposit(owner eos.AccountName, amount eos.Asset) (string, error) {
+	if err := m.Chain.CheckCoreAsset(amount); err != nil {
+		return "", err
+	}
 	actionData := make(map[string]interface{})
 	actionData["owner"] = owner
 	actionData["amount"] = amount
@@ -98,6 +118,9 @@ func (m *RexContract) Deposit(owner eos.AccountName, amount eos.Asset) (string,
 }
 
 func (m *RexContract) BuyRex(from eos.AccountName, amount eos.Asset) (string, error) {
+	if err := m.Chain.CheckCoreAsset(amount); err != nil {
+		return "", err
+	}
 	actionData := make(map[string]interface{})
 	actionData["from"] = from
 	actionData["amount"] = amount
@@ -106,6 +129,9 @@ func (m *RexContract) BuyRex(from eos.AccountName, amount eos.Asset) (string, er
 }
 
 func (m *RexContract) MoveToSavings(owner eos.AccountName, rex eos.Asset) (string, error) {
+	if err := m.Chain.CheckRexAsset(rex); err != nil {
+		return "", err
+	}
 	actionData := make(map[string]interface{})
 	actionData["owner"] = owner
 	actionData["rex"] = rex
@@ -114,6 +140,9 @@ func (m *RexContract) MoveToSavings(owner eos.AccountName, rex eos.Asset) (strin
 }
 
 func (m *RexContract) MoveFromSavings(owner eos.AccountName, rex eos.Asset) (string, error) {
+	if err := m.Chain.CheckRexAsset(rex); err != nil {
+		return "", err
+	}
 	actionData := make(map[string]interface{})
 	actionData["owner"] = owner
 	actionData["rex"] = rex
@@ -122,6 +151,9 @@ func (m *RexContract) MoveFromSavings(owner eos.AccountName, rex eos.Asset) (str
 }
 
 func (m *RexContract) SellRex(from eos.AccountName, rex eos.Asset) (string, error) {
+	if err := m.Chain.CheckRexAsset(rex); err != nil {
+		return "", err
+	}
 	actionData := make(map[string]interface{})
 	actionData["from"] = from
 	actionData["rex"] = rex
@@ -130,6 +162,9 @@ func (m *RexContract) SellRex(from eos.AccountName, rex eos.Asset) (string, erro
 }
 
 func (m *RexContract) Withdraw(owner eos.AccountName, amount eos.Asset) (string, error) {
+	if err := m.Chain.CheckCoreAsset(amount); err != nil {
+		return "", err
+	}
 	actionData := make(map[string]interface{})
 	actionData["owner"] = owner
 	actionData["amount"] = amount
@@ -0,0 +1,302 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package rex
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/internal/tablepage"
+)
+
+// SavingsMaturity is the maturity used by the system contract for the REX in savings
+var SavingsMaturity = time.Unix(int64(^uint32(0)), 0).UTC()
+
+func parseAsset(value, field string) (eos.Asset, error) {
+	asset, err := eos.NewAssetFromString(value)
+	if err != nil {
+		return eos.Asset{}, fmt.Errorf("invalid %v: %v, error: %v", field, value, err)
+	}
+	return asset, nil
+}
+
+func (m *Balance) GetFundInBalance() (eos.Asset, error) {
+	return parseAsset(m.FundInBalance, "fund in balance")
+}
+
+func (m *Balance) GetRexBought() (eos.Asset, error) {
+	return parseAsset(m.RexBought, "rex bought")
+}
+
+func (m *Balance) GetRexInSavings() (eos.Asset, error) {
+	return parseAsset(m.RexInSavings, "rex in savings")
+}
+
+func (m *Balance) GetRexLiquid() (eos.Asset, error) {
+	return parseAsset(m.RexLiquid, "rex liquid")
+}
+
+func (m *Balance) GetRexInSellOrders() (eos.Asset, error) {
+	return parseAsset(m.RexInSellOrders, "rex in sell orders")
+}
+
+func (m *Balance) GetFundOutBalance() (eos.Asset, error) {
+	return parseAsset(m.FundOutBalance, "fund out balance")
+}
+
+// RexFund is a row of the rexfund table, the funds available to buy REX or to be withdrawn
+type RexFund struct {
+	Version uint8           `json:"version"`
+	Owner   eos.AccountName `json:"owner"`
+	Balance string          `json:"balance"`
+}
+
+func (m *RexFund) GetBalance() (eos.Asset, error) {
+	return parseAsset(m.Balance, "balance")
+}
+
+// RexOrder is a row of the rexqueue table, a sell order waiting for enough unlent funds to be filled
+type RexOrder struct {
+	Version      uint8           `json:"version"`
+	Owner        eos.AccountName `json:"owner"`
+	RexRequested string          `json:"rex_requested"`
+	Proceeds     string          `json:"proceeds"`
+	StakeChange  string          `json:"stake_change"`
+	OrderTime    string          `json:"order_time"`
+	IsOpen       bool            `json:"is_open"`
+}
+
+func (m *RexOrder) GetRexRequested() (eos.Asset, error) {
+	return parseAsset(m.RexRequested, "rex requested")
+}
+
+func (m *RexOrder) GetProceeds() (eos.Asset, error) {
+	return parseAsset(m.Proceeds, "proceeds")
+}
+
+func (m *RexOrder) GetOrderTime() (time.Time, error) {
+	orderTime, err := time.Parse("2006-01-02T15:04:05", m.OrderTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid order time: %v, error: %v", m.OrderTime, err)
+	}
+	return orderTime, nil
+}
+
+type RexMaturity struct {
+	First  string    `json:"first"`
+	Second eos.Int64 `json:"second"`
+}
+
+// RexBal is a row of the rexbal table, MaturedRex is the amount of REX that can be sold, the rest is held in
+// maturity buckets
+type RexBal struct {
+	Version       uint8           `json:"version"`
+	Owner         eos.AccountName `json:"owner"`
+	VoteStake     string          `json:"vote_stake"`
+	RexBalance    string          `json:"rex_balance"`
+	MaturedRex    eos.Int64       `json:"matured_rex"`
+	RexMaturities []RexMaturity   `json:"rex_maturities"`
+}
+
+// MaturityBucket is an amount of REX that can be sold after the unlock time, savings buckets never unlock
+// until they are moved from savings
+type MaturityBucket struct {
+	Amount     eos.Asset
+	UnlockTime time.Time
+	IsSavings  bool
+}
+
+func (m *RexBal) GetRexBalance() (eos.Asset, error) {
+	return parseAsset(m.RexBalance, "rex balance")
+}
+
+// GetMaturities returns the maturity buckets sorted by unlock time
+func (m *RexBal) GetMaturities() ([]*MaturityBucket, error) {
+	rexBalance, err := m.GetRexBalance()
+	if err != nil {
+		return nil, err
+	}
+	buckets := make([]*MaturityBucket, 0, len(m.RexMaturities))
+	for _, maturity := range m.RexMaturities {
+		unlockTime, err := time.Parse("2006-01-02T15:04:05", maturity.First)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maturity: %v, error: %v", maturity.First, err)
+		}
+		buckets = append(buckets, &MaturityBucket{
+			Amount:     eos.Asset{Amount: maturity.Second, Symbol: rexBalance.Symbol},
+			UnlockTime: unlockTime,
+			IsSavings:  !unlockTime.Before(SavingsMaturity),
+		})
+	}
+	sort.SliceStable(buckets, func(i, j int) bool {
+		return buckets[i].UnlockTime.Before(buckets[j].UnlockTime)
+	})
+	return buckets, nil
+}
+
+// MaturedRexAt returns the amount of REX that can be sold at the specified time
+func (m *RexBal) MaturedRexAt(t time.Time) (eos.Asset, error) {
+	rexBalance, err := m.GetRexBalance()
+	if err != nil {
+		return eos.Asset{}, err
+	}
+	buckets, err := m.GetMaturities()
+	if err != nil {
+		return eos.Asset{}, err
+	}
+	matured := eos.Asset{Amount: m.MaturedRex, Symbol: rexBalance.Symbol}
+	for _, bucket := range buckets {
+		if !bucket.IsSavings && !bucket.UnlockTime.After(t) {
+			matured.Amount += bucket.Amount.Amount
+		}
+	}
+	return matured, nil
+}
+
+// MaturityTimeFor returns the earliest time at which the amount of REX can be sold, now if the amount is already
+// matured, false if the amount will not mature because it is in savings or exceeds the balance
+func (m *RexBal) MaturityTimeFor(amount eos.Asset, now time.Time) (time.Time, bool, error) {
+	rexBalance, err := m.GetRexBalance()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if !sameSymbol(amount.Symbol, rexBalance.Symbol) {
+		return time.Time{}, false, fmt.Errorf("amount: %v does not match rex symbol: %v", amount, rexBalance.Symbol)
+	}
+	buckets, err := m.GetMaturities()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	matured := m.MaturedRex
+	for _, bucket := range buckets {
+		if !bucket.UnlockTime.After(now) && !bucket.IsSavings {
+			matured += bucket.Amount.Amount
+		}
+	}
+	if matured >= amount.Amount {
+		return now, true, nil
+	}
+	for _, bucket := range buckets {
+		if bucket.IsSavings || !bucket.UnlockTime.After(now) {
+			continue
+		}
+		matured += bucket.Amount.Amount
+		if matured >= amount.Amount {
+			return bucket.UnlockTime, true, nil
+		}
+	}
+	return time.Time{}, false, nil
+}
+
+func (m *RexContract) GetRexFund(owner eos.AccountName) (*RexFund, error) {
+	var funds []RexFund
+	err := m.GetTableRows(eos.GetTableRowsRequest{
+		Table:      "rexfund",
+		LowerBound: string(owner),
+		UpperBound: string(owner),
+		Limit:      1,
+	}, &funds)
+	if err != nil {
+		return nil, fmt.Errorf("get table rows %v", err)
+	}
+	if len(funds) > 0 {
+		return &funds[0], nil
+	}
+	return nil, nil
+}
+
+func (m *RexContract) GetRexOrder(owner eos.AccountName) (*RexOrder, error) {
+	var orders []RexOrder
+	err := m.GetTableRows(eos.GetTableRowsRequest{
+		Table:      "rexqueue",
+		LowerBound: string(owner),
+		UpperBound: string(owner),
+		Limit:      1,
+	}, &orders)
+	if err != nil {
+		return nil, fmt.Errorf("get table rows %v", err)
+	}
+	if len(orders) > 0 {
+		return &orders[0], nil
+	}
+	return nil, nil
+}
+
+// GetOpenRexOrders returns the open sell orders ordered by order time, which is the order in which they are filled
+func (m *RexContract) GetOpenRexOrders() ([]RexOrder, error) {
+	orders := make([]RexOrder, 0)
+	err := tablepage.ReadAll(func(lowerBound string) (int, bool, error) {
+		var page []RexOrder
+		more, err := tablepage.GetTableRows(m.Contract, eos.GetTableRowsRequest{
+			Table:      "rexqueue",
+			LowerBound: lowerBound,
+		}, &page)
+		orders = append(orders, page...)
+		return len(page), more, err
+	}, func() (string, error) {
+		return tablepage.AfterName(string(orders[len(orders)-1].Owner)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return SortOpenRexOrders(orders)
+}
+
+// SortOpenRexOrders filters the open orders and sorts them by order time
+func SortOpenRexOrders(orders []RexOrder) ([]RexOrder, error) {
+	open := make([]RexOrder, 0, len(orders))
+	times := make(map[eos.AccountName]time.Time)
+	for _, order := range orders {
+		if !order.IsOpen {
+			continue
+		}
+		orderTime, err := order.GetOrderTime()
+		if err != nil {
+			return nil, err
+		}
+		times[order.Owner] = orderTime
+		open = append(open, order)
+	}
+	sort.SliceStable(open, func(i, j int) bool {
+		return times[open[i].Owner].Before(times[open[j].Owner])
+	})
+	return open, nil
+}
+
+func (m *RexContract) GetRexBal(owner eos.AccountName) (*RexBal, error) {
+	var balances []RexBal
+	err := m.GetTableRows(eos.GetTableRowsRequest{
+		Table:      "rexbal",
+		LowerBound: string(owner),
+		UpperBound: string(owner),
+		Limit:      1,
+	}, &balances)
+	if err != nil {
+		return nil, fmt.Errorf("get table rows %v", err)
+	}
+	if len(balances) > 0 {
+		return &balances[0], nil
+	}
+	return nil, nil
+}
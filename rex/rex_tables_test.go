@@ -0,0 +1,56 @@
+package rex_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eoscanada/eos-go"
+
+	"github.com/sebastianmontero/bennyfi-go-client/rex"
+	"gotest.tools/assert"
+)
+
+func TestRexBalMaturities(t *testing.T) {
+	rexBal := &rex.RexBal{
+		RexBalance: "400.0000 REX",
+		MaturedRex: 1000000,
+		RexMaturities: []rex.RexMaturity{
+			{First: "2106-02-07T06:28:15", Second: 1000000},
+			{First: "2021-01-03T00:00:00", Second: 1000000},
+			{First: "2021-01-02T00:00:00", Second: 1000000},
+		},
+	}
+	now := time.Date(2021, 1, 2, 12, 0, 0, 0, time.UTC)
+	buckets, err := rexBal.GetMaturities()
+	assert.NilError(t, err)
+	assert.Equal(t, len(buckets), 3)
+	assert.Assert(t, buckets[2].IsSavings)
+
+	matured, err := rexBal.MaturedRexAt(now)
+	assert.NilError(t, err)
+	assert.Equal(t, matured.String(), "200.0000 REX")
+
+	maturityTime, ok, err := rexBal.MaturityTimeFor(rexAsset(250), now)
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+	assert.Equal(t, maturityTime, time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC))
+
+	_, ok, err = rexBal.MaturityTimeFor(rexAsset(350), now)
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+}
+
+func TestSortOpenRexOrders(t *testing.T) {
+	orders, err := rex.SortOpenRexOrders([]rex.RexOrder{
+		{Owner: "bob", OrderTime: "2021-06-30T12:00:00.500", IsOpen: true},
+		{Owner: "carol", OrderTime: "2021-06-28T00:00:00", IsOpen: false},
+		{Owner: "alice", OrderTime: "2021-06-30T12:00:00", IsOpen: true},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(orders), 2)
+	assert.Equal(t, orders[0].Owner, eos.AccountName("alice"))
+	assert.Equal(t, orders[1].Owner, eos.AccountName("bob"))
+
+	_, err = rex.SortOpenRexOrders([]rex.RexOrder{{Owner: "bob", OrderTime: "yesterday", IsOpen: true}})
+	assert.ErrorContains(t, err, "invalid order time")
+}
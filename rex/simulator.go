@@ -0,0 +1,358 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package rex
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/chain"
+)
+
+type simulatedBalance struct {
+	owner           eos.AccountName
+	fundIn          eos.Int64
+	rexBought       eos.Int64
+	rexInSavings    eos.Int64
+	rexLiquid       eos.Int64
+	rexInSellOrders eos.Int64
+	fundOut         eos.Int64
+}
+
+// Simulator is an in memory implementation of RexClient that models the pool math. The lendable increment is added
+// to the total lendable before every buyrex and sellrex, simulating the pool earnings. Funds can be marked as lent
+// with SetLentFunds, sellrex creates a sell order when the unlent funds do not cover the proceeds, sell orders are
+// filled in order when funds are returned.
+type Simulator struct {
+	mutex         sync.Mutex
+	chain         chain.Chain
+	initialized   bool
+	config        Config
+	totalLendable eos.Asset
+	totalRex      eos.Asset
+	lentFunds     eos.Int64
+	balances      map[eos.AccountName]*simulatedBalance
+	sellOrders    []eos.AccountName
+	txCounter     uint64
+}
+
+var _ RexClient = (*RexContract)(nil)
+var _ RexClient = (*Simulator)(nil)
+
+// NewSimulator creates a simulator of the rex of the chain, errors if the chain is not valid
+func NewSimulator(chain chain.Chain) (*Simulator, error) {
+	err := chain.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return &Simulator{
+		chain:    chain,
+		balances: make(map[eos.AccountName]*simulatedBalance),
+	}, nil
+}
+
+func (m *Simulator) txID() string {
+	m.txCounter++
+	return fmt.Sprintf("Tx ID: %064x", m.txCounter)
+}
+
+func (m *Simulator) checkInitialized() error {
+	if !m.initialized {
+		return fmt.Errorf("rex pool has not been initialized")
+	}
+	return nil
+}
+
+func (m *Simulator) checkAmount(amount eos.Asset, symbol eos.Symbol, field string) error {
+	if !sameSymbol(amount.Symbol, symbol) {
+		return fmt.Errorf("%v: %v symbol does not match: %v", field, amount, symbol)
+	}
+	if amount.Amount <= 0 {
+		return fmt.Errorf("%v: %v must be positive", field, amount)
+	}
+	return nil
+}
+
+func (m *Simulator) getBalance(owner eos.AccountName) *simulatedBalance {
+	balance, ok := m.balances[owner]
+	if !ok {
+		balance = &simulatedBalance{owner: owner}
+		m.balances[owner] = balance
+	}
+	return balance
+}
+
+func (m *Simulator) Init(totalLendable, totalRex eos.Asset, lendableIncrement uint64) (string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.initialized {
+		return "", fmt.Errorf("rex pool already initialized")
+	}
+	if err := m.chain.CheckCoreAsset(totalLendable); err != nil {
+		return "", err
+	}
+	if err := m.chain.CheckRexAsset(totalRex); err != nil {
+		return "", err
+	}
+	if totalLendable.Amount < 0 || totalRex.Amount < 0 {
+		return "", fmt.Errorf("total lendable and total rex must not be negative")
+	}
+	m.initialized = true
+	m.totalLendable = totalLendable
+	m.totalRex = totalRex
+	m.config = Config{LendableIncrement: lendableIncrement}
+	return m.txID(), nil
+}
+
+func (m *Simulator) Deposit(owner eos.AccountName, amount eos.Asset) (string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if err := m.checkInitialized(); err != nil {
+		return "", err
+	}
+	if err := m.checkAmount(amount, m.totalLendable.Symbol, "amount"); err != nil {
+		return "", err
+	}
+	m.getBalance(owner).fundIn += amount.Amount
+	return m.txID(), nil
+}
+
+func (m *Simulator) accrue() {
+	m.totalLendable.Amount += eos.Int64(m.config.LendableIncrement)
+}
+
+func (m *Simulator) pool() *RexPool {
+	return &RexPool{
+		TotalLendable: m.totalLendable.String(),
+		TotalRex:      m.totalRex.String(),
+	}
+}
+
+func (m *Simulator) BuyRex(from eos.AccountName, amount eos.Asset) (string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if err := m.checkInitialized(); err != nil {
+		return "", err
+	}
+	if err := m.checkAmount(amount, m.totalLendable.Symbol, "amount"); err != nil {
+		return "", err
+	}
+	balance := m.getBalance(from)
+	if balance.fundIn < amount.Amount {
+		return "", fmt.Errorf("insufficient funds, fund in balance: %v", eos.Asset{Amount: balance.fundIn, Symbol: amount.Symbol})
+	}
+	m.accrue()
+	rex, err := m.pool().FundToRex(amount)
+	if err != nil {
+		return "", err
+	}
+	balance.fundIn -= amount.Amount
+	balance.rexBought += rex.Amount
+	balance.rexLiquid += rex.Amount
+	m.totalLendable.Amount += amount.Amount
+	m.totalRex.Amount += rex.Amount
+	return m.txID(), nil
+}
+
+func (m *Simulator) MoveToSavings(owner eos.AccountName, rex eos.Asset) (string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if err := m.checkInitialized(); err != nil {
+		return "", err
+	}
+	if err := m.checkAmount(rex, m.totalRex.Symbol, "rex"); err != nil {
+		return "", err
+	}
+	balance := m.getBalance(owner)
+	if balance.rexLiquid < rex.Amount {
+		return "", fmt.Errorf("insufficient liquid rex: %v", eos.Asset{Amount: balance.rexLiquid, Symbol: rex.Symbol})
+	}
+	balance.rexLiquid -= rex.Amount
+	balance.rexInSavings += rex.Amount
+	return m.txID(), nil
+}
+
+func (m *Simulator) MoveFromSavings(owner eos.AccountName, rex eos.Asset) (string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if err := m.checkInitialized(); err != nil {
+		return "", err
+	}
+	if err := m.checkAmount(rex, m.totalRex.Symbol, "rex"); err != nil {
+		return "", err
+	}
+	balance := m.getBalance(owner)
+	if balance.rexInSavings < rex.Amount {
+		return "", fmt.Errorf("insufficient rex in savings: %v", eos.Asset{Amount: balance.rexInSavings, Symbol: rex.Symbol})
+	}
+	balance.rexInSavings -= rex.Amount
+	balance.rexLiquid += rex.Amount
+	return m.txID(), nil
+}
+
+// SellRex sells the rex at the pool rate, if the unlent funds do not cover the proceeds the rex is moved to a
+// sell order, only one sell order per owner is allowed
+func (m *Simulator) SellRex(from eos.AccountName, rex eos.Asset) (string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if err := m.checkInitialized(); err != nil {
+		return "", err
+	}
+	if err := m.checkAmount(rex, m.totalRex.Symbol, "rex"); err != nil {
+		return "", err
+	}
+	balance := m.getBalance(from)
+	if balance.rexLiquid < rex.Amount {
+		return "", fmt.Errorf("insufficient liquid rex: %v", eos.Asset{Amount: balance.rexLiquid, Symbol: rex.Symbol})
+	}
+	if balance.rexInSellOrders > 0 {
+		return "", fmt.Errorf("sell order already exists for: %v", from)
+	}
+	m.accrue()
+	balance.rexLiquid -= rex.Amount
+	if !m.sell(balance, rex.Amount) {
+		balance.rexInSellOrders = rex.Amount
+		m.sellOrders = append(m.sellOrders, from)
+	}
+	return m.txID(), nil
+}
+
+// sell executes the sale if the unlent funds cover the proceeds
+func (m *Simulator) sell(balance *simulatedBalance, rex eos.Int64) bool {
+	proceeds, err := m.pool().RexToFund(eos.Asset{Amount: rex, Symbol: m.totalRex.Symbol})
+	if err != nil || proceeds.Amount > m.totalLendable.Amount-m.lentFunds {
+		return false
+	}
+	m.totalLendable.Amount -= proceeds.Amount
+	m.totalRex.Amount -= rex
+	balance.fundOut += proceeds.Amount
+	return true
+}
+
+// SetLentFunds sets the amount of the total lendable that is currently lent and can not be used to pay sell
+// orders, pending sell orders are filled in order if enough funds are available
+func (m *Simulator) SetLentFunds(lent eos.Asset) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if err := m.checkInitialized(); err != nil {
+		return err
+	}
+	if !sameSymbol(lent.Symbol, m.totalLendable.Symbol) || lent.Amount < 0 {
+		return fmt.Errorf("invalid lent funds: %v", lent)
+	}
+	m.lentFunds = lent.Amount
+	for len(m.sellOrders) > 0 {
+		balance := m.balances[m.sellOrders[0]]
+		if !m.sell(balance, balance.rexInSellOrders) {
+			break
+		}
+		balance.rexInSellOrders = 0
+		m.sellOrders = m.sellOrders[1:]
+	}
+	return nil
+}
+
+// Withdraw takes the amount from the sale proceeds first and then from the deposited funds
+func (m *Simulator) Withdraw(owner eos.AccountName, amount eos.Asset) (string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if err := m.checkInitialized(); err != nil {
+		return "", err
+	}
+	if err := m.checkAmount(amount, m.totalLendable.Symbol, "amount"); err != nil {
+		return "", err
+	}
+	balance := m.getBalance(owner)
+	if balance.fundOut+balance.fundIn < amount.Amount {
+		return "", fmt.Errorf("insufficient funds, available: %v", eos.Asset{Amount: balance.fundOut + balance.fundIn, Symbol: amount.Symbol})
+	}
+	fromFundOut := amount.Amount
+	if fromFundOut > balance.fundOut {
+		fromFundOut = balance.fundOut
+	}
+	balance.fundOut -= fromFundOut
+	balance.fundIn -= amount.Amount - fromFundOut
+	return m.txID(), nil
+}
+
+func (m *Simulator) GetConfig() (*Config, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if !m.initialized {
+		return nil, nil
+	}
+	config := m.config
+	return &config, nil
+}
+
+func (m *Simulator) GetBalance(owner eos.Name) (*Balance, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	balance, ok := m.balances[eos.AccountName(owner)]
+	if !ok {
+		return nil, nil
+	}
+	return m.toBalance(balance), nil
+}
+
+// GetBalances returns all the balances sorted by owner
+func (m *Simulator) GetBalances() []Balance {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	balances := make([]Balance, 0, len(m.balances))
+	for _, balance := range m.balances {
+		balances = append(balances, *m.toBalance(balance))
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].Owner < balances[j].Owner
+	})
+	return balances
+}
+
+func (m *Simulator) toBalance(balance *simulatedBalance) *Balance {
+	fund := func(amount eos.Int64) string {
+		return eos.Asset{Amount: amount, Symbol: m.totalLendable.Symbol}.String()
+	}
+	rex := func(amount eos.Int64) string {
+		return eos.Asset{Amount: amount, Symbol: m.totalRex.Symbol}.String()
+	}
+	return &Balance{
+		Owner:           balance.owner,
+		FundInBalance:   fund(balance.fundIn),
+		RexBought:       rex(balance.rexBought),
+		RexInSavings:    rex(balance.rexInSavings),
+		RexLiquid:       rex(balance.rexLiquid),
+		RexInSellOrders: rex(balance.rexInSellOrders),
+		FundOutBalance:  fund(balance.fundOut),
+	}
+}
+
+func (m *Simulator) GetPool() (*RexPool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if !m.initialized {
+		return nil, nil
+	}
+	return m.pool(), nil
+}
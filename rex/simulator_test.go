@@ -0,0 +1,118 @@
+package rex_test
+
+import (
+	"testing"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/chain"
+	"github.com/sebastianmontero/bennyfi-go-client/rex"
+	"gotest.tools/assert"
+)
+
+func fund(amount int64) eos.Asset {
+	return chain.Telos().CoreAsset(amount * 10000)
+}
+
+func rexAsset(amount int64) eos.Asset {
+	return chain.Telos().RexAsset(amount * 10000)
+}
+
+func TestSimulator(t *testing.T) {
+	simulator, err := rex.NewSimulator(chain.Telos())
+	assert.NilError(t, err)
+	var client rex.RexClient = simulator
+	_, err = client.Deposit("alice", fund(10))
+	assert.ErrorContains(t, err, "not been initialized")
+
+	_, err = client.Init(chain.EOS().CoreAsset(10000000), rexAsset(10000000), 100000)
+	assert.ErrorContains(t, err, "does not match core symbol")
+	_, err = client.Init(fund(1000), fund(10000000), 100000)
+	assert.ErrorContains(t, err, "does not match rex symbol")
+	_, err = client.Init(fund(1000), rexAsset(10000000), 100000)
+	assert.NilError(t, err)
+	_, err = client.Deposit("alice", fund(100))
+	assert.NilError(t, err)
+	_, err = client.BuyRex("alice", fund(101))
+	assert.ErrorContains(t, err, "insufficient funds")
+
+	// the increment of 10 TLOS is accrued before buying, 100 TLOS buy 100 * 10000000 / 1010 REX
+	_, err = client.BuyRex("alice", fund(100))
+	assert.NilError(t, err)
+	balance, err := client.GetBalance("alice")
+	assert.NilError(t, err)
+	assert.Equal(t, balance.RexLiquid, "990099.0099 REX")
+	assert.Equal(t, balance.FundInBalance, "0.0000 TLOS")
+	pool, err := client.GetPool()
+	assert.NilError(t, err)
+	assert.Equal(t, pool.TotalLendable, "1110.0000 TLOS")
+
+	rexAmount, _ := eos.NewAssetFromString(balance.RexLiquid)
+	_, err = client.MoveToSavings("alice", rexAmount)
+	assert.NilError(t, err)
+	_, err = client.SellRex("alice", rexAmount)
+	assert.ErrorContains(t, err, "insufficient liquid rex")
+	_, err = client.MoveFromSavings("alice", rexAmount)
+	assert.NilError(t, err)
+
+	_, err = client.SellRex("alice", rexAmount)
+	assert.NilError(t, err)
+	balance, err = client.GetBalance("alice")
+	assert.NilError(t, err)
+	assert.Equal(t, balance.RexLiquid, "0.0000 REX")
+	assert.Equal(t, balance.FundOutBalance, "100.9009 TLOS")
+
+	_, err = client.Withdraw("alice", fund(101))
+	assert.ErrorContains(t, err, "insufficient funds")
+	_, err = client.Withdraw("alice", fund(100))
+	assert.NilError(t, err)
+	balance, err = client.GetBalance("alice")
+	assert.NilError(t, err)
+	assert.Equal(t, balance.FundOutBalance, "0.9009 TLOS")
+}
+
+func TestSimulatorSellOrders(t *testing.T) {
+	simulator, err := rex.NewSimulator(chain.Telos())
+	assert.NilError(t, err)
+	_, err = simulator.Init(fund(1000), rexAsset(10000000), 0)
+	assert.NilError(t, err)
+	_, err = simulator.Deposit("bob", fund(100))
+	assert.NilError(t, err)
+	_, err = simulator.BuyRex("bob", fund(100))
+	assert.NilError(t, err)
+	assert.NilError(t, simulator.SetLentFunds(fund(1050)))
+
+	_, err = simulator.SellRex("bob", rexAsset(1000000))
+	assert.NilError(t, err)
+	balance, _ := simulator.GetBalance("bob")
+	assert.Equal(t, balance.RexInSellOrders, "1000000.0000 REX")
+	assert.Equal(t, balance.FundOutBalance, "0.0000 TLOS")
+
+	assert.NilError(t, simulator.SetLentFunds(fund(0)))
+	balance, _ = simulator.GetBalance("bob")
+	assert.Equal(t, balance.RexInSellOrders, "0.0000 REX")
+	assert.Equal(t, balance.FundOutBalance, "100.0000 TLOS")
+}
+
+func TestRexContractChains(t *testing.T) {
+	mock := chain.Telos()
+	mock.RexContract = eos.AN("rexmock")
+	telos, err := rex.NewRexContract(nil, mock)
+	assert.NilError(t, err)
+	eosio, err := rex.NewRexContract(nil, chain.EOS())
+	assert.NilError(t, err)
+	assert.Equal(t, telos.ContractName, "rexmock")
+	assert.Equal(t, telos.Chain.CoreSymbol.Symbol, "TLOS")
+	assert.Equal(t, eosio.ContractName, "eosio")
+	assert.Equal(t, eosio.Chain.CoreSymbol.Symbol, "EOS")
+
+	_, err = eosio.Deposit("alice", fund(1))
+	assert.ErrorContains(t, err, "does not match core symbol")
+	_, err = eosio.SellRex("alice", fund(1))
+	assert.ErrorContains(t, err, "does not match rex symbol")
+
+	mock.CoreSymbol = eos.Symbol{}
+	_, err = rex.NewRexContract(nil, mock)
+	assert.ErrorContains(t, err, "has no core symbol")
+	_, err = rex.NewSimulator(mock)
+	assert.ErrorContains(t, err, "has no core symbol")
+}
@@ -0,0 +1,145 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2020, Digital Scarcity
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package rex
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/eoscanada/eos-go"
+)
+
+// InitialRexRatio is the number of REX units received per fund unit when the pool is empty
+const InitialRexRatio int64 = 10000
+
+func (m *RexPool) GetTotalLendable() (eos.Asset, error) {
+	totalLendable, err := eos.NewAssetFromString(m.TotalLendable)
+	if err != nil {
+		return eos.Asset{}, fmt.Errorf("invalid total lendable: %v, error: %v", m.TotalLendable, err)
+	}
+	return totalLendable, nil
+}
+
+func (m *RexPool) GetTotalRex() (eos.Asset, error) {
+	totalRex, err := eos.NewAssetFromString(m.TotalRex)
+	if err != nil {
+		return eos.Asset{}, fmt.Errorf("invalid total rex: %v, error: %v", m.TotalRex, err)
+	}
+	return totalRex, nil
+}
+
+func (m *RexPool) getTotals() (eos.Asset, eos.Asset, error) {
+	totalLendable, err := m.GetTotalLendable()
+	if err != nil {
+		return eos.Asset{}, eos.Asset{}, err
+	}
+	totalRex, err := m.GetTotalRex()
+	if err != nil {
+		return eos.Asset{}, eos.Asset{}, err
+	}
+	return totalLendable, totalRex, nil
+}
+
+// RexToFund values the rex amount in the fund symbol at the current pool rate, rounding down as sellrex does
+func (m *RexPool) RexToFund(rex eos.Asset) (eos.Asset, error) {
+	totalLendable, totalRex, err := m.getTotals()
+	if err != nil {
+		return eos.Asset{}, err
+	}
+	if !sameSymbol(rex.Symbol, totalRex.Symbol) {
+		return eos.Asset{}, fmt.Errorf("rex amount: %v does not match pool rex symbol: %v", rex, totalRex.Symbol)
+	}
+	if totalRex.Amount <= 0 {
+		return eos.Asset{}, fmt.Errorf("can not value rex, pool has no rex")
+	}
+	amount, err := mulDiv(int64(rex.Amount), int64(totalLendable.Amount), int64(totalRex.Amount))
+	if err != nil {
+		return eos.Asset{}, fmt.Errorf("failed valuing rex: %v, error: %v", rex, err)
+	}
+	return eos.Asset{Amount: amount, Symbol: totalLendable.Symbol}, nil
+}
+
+// FundToRex calculates the rex that would be bought with the fund amount at the current pool rate
+func (m *RexPool) FundToRex(fund eos.Asset) (eos.Asset, error) {
+	totalLendable, totalRex, err := m.getTotals()
+	if err != nil {
+		return eos.Asset{}, err
+	}
+	if !sameSymbol(fund.Symbol, totalLendable.Symbol) {
+		return eos.Asset{}, fmt.Errorf("fund amount: %v does not match pool fund symbol: %v", fund, totalLendable.Symbol)
+	}
+	var amount eos.Int64
+	if totalLendable.Amount <= 0 || totalRex.Amount <= 0 {
+		amount, err = mulDiv(int64(fund.Amount), InitialRexRatio, 1)
+	} else {
+		amount, err = mulDiv(int64(fund.Amount), int64(totalRex.Amount), int64(totalLendable.Amount))
+	}
+	if err != nil {
+		return eos.Asset{}, fmt.Errorf("failed calculating rex for: %v, error: %v", fund, err)
+	}
+	return eos.Asset{Amount: amount, Symbol: totalRex.Symbol}, nil
+}
+
+// Rate returns the value of one REX unit in fund units
+func (m *RexPool) Rate() (float64, error) {
+	totalLendable, totalRex, err := m.getTotals()
+	if err != nil {
+		return 0, err
+	}
+	if totalRex.Amount <= 0 {
+		return 1 / float64(InitialRexRatio), nil
+	}
+	return float64(totalLendable.Amount) / float64(totalRex.Amount), nil
+}
+
+// EstimateAPR annualizes the change in the pool rate between two snapshots taken elapsed time apart
+func EstimateAPR(before, after *RexPool, elapsed time.Duration) (float64, error) {
+	if elapsed <= 0 {
+		return 0, fmt.Errorf("elapsed time must be positive")
+	}
+	rateBefore, err := before.Rate()
+	if err != nil {
+		return 0, err
+	}
+	rateAfter, err := after.Rate()
+	if err != nil {
+		return 0, err
+	}
+	year := float64(365 * 24 * time.Hour)
+	return (rateAfter/rateBefore - 1) * year / float64(elapsed), nil
+}
+
+// mulDiv calculates a * b / c without overflowing the intermediate product, errors if the result does not fit
+// in an int64
+func mulDiv(a, b, c int64) (eos.Int64, error) {
+	result := new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+	result.Quo(result, big.NewInt(c))
+	if !result.IsInt64() {
+		return 0, fmt.Errorf("%v * %v / %v overflows int64", a, b, c)
+	}
+	return eos.Int64(result.Int64()), nil
+}
+
+func sameSymbol(s1, s2 eos.Symbol) bool {
+	return s1.Symbol == s2.Symbol && s1.Precision == s2.Precision
+}
@@ -0,0 +1,57 @@
+package rex_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/sebastianmontero/bennyfi-go-client/rex"
+	"gotest.tools/assert"
+)
+
+func TestRexToFund(t *testing.T) {
+	pool := &rex.RexPool{TotalLendable: "1100.0000 TLOS", TotalRex: "10000000.0000 REX"}
+	fund, err := pool.RexToFund(eos.Asset{Amount: 10000000000, Symbol: eos.Symbol{Precision: 4, Symbol: "REX"}})
+	assert.NilError(t, err)
+	assert.Equal(t, fund.String(), "110.0000 TLOS")
+
+	fund, err = pool.RexToFund(eos.Asset{Amount: 1, Symbol: eos.Symbol{Precision: 4, Symbol: "REX"}})
+	assert.NilError(t, err)
+	assert.Equal(t, fund.String(), "0.0000 TLOS")
+
+	_, err = pool.RexToFund(eos.Asset{Amount: 1, Symbol: eos.Symbol{Precision: 4, Symbol: "TLOS"}})
+	assert.ErrorContains(t, err, "does not match pool rex symbol")
+
+	_, err = (&rex.RexPool{TotalLendable: "0.0000 TLOS", TotalRex: "0.0000 REX"}).RexToFund(eos.Asset{Amount: 1, Symbol: eos.Symbol{Precision: 4, Symbol: "REX"}})
+	assert.ErrorContains(t, err, "pool has no rex")
+}
+
+func TestFundToRex(t *testing.T) {
+	tlos := eos.Asset{Amount: 10000, Symbol: eos.Symbol{Precision: 4, Symbol: "TLOS"}}
+	pool := &rex.RexPool{TotalLendable: "1100.0000 TLOS", TotalRex: "11000000.0000 REX"}
+	rexAmount, err := pool.FundToRex(tlos)
+	assert.NilError(t, err)
+	assert.Equal(t, rexAmount.String(), "10000.0000 REX")
+
+	rexAmount, err = (&rex.RexPool{TotalLendable: "0.0000 TLOS", TotalRex: "0.0000 REX"}).FundToRex(tlos)
+	assert.NilError(t, err)
+	assert.Equal(t, rexAmount.Amount, eos.Int64(10000*rex.InitialRexRatio))
+
+	_, err = (&rex.RexPool{TotalLendable: "0.0001 TLOS", TotalRex: "100000000000000.0000 REX"}).FundToRex(tlos)
+	assert.ErrorContains(t, err, "overflows int64")
+
+	_, err = pool.FundToRex(eos.Asset{Amount: 1, Symbol: eos.Symbol{Precision: 4, Symbol: "REX"}})
+	assert.ErrorContains(t, err, "does not match pool fund symbol")
+}
+
+func TestEstimateAPR(t *testing.T) {
+	before := &rex.RexPool{TotalLendable: "1000.0000 TLOS", TotalRex: "10000000.0000 REX"}
+	after := &rex.RexPool{TotalLendable: "1050.0000 TLOS", TotalRex: "10000000.0000 REX"}
+	apr, err := rex.EstimateAPR(before, after, 365*24*time.Hour/2)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(apr-0.1) < 1e-9, apr)
+
+	_, err = rex.EstimateAPR(before, after, 0)
+	assert.ErrorContains(t, err, "elapsed time must be positive")
+}